@@ -1,11 +1,31 @@
 package level
 
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
 func (l *Level) NewAtom() *Atom {
 	return &Atom{
 		l.NewWriteBatch(),
 	}
 }
 
+/*
+	Function NewAtom returns a new Atom of the Level the Database was opened with.
+*/
+func (d *Database) NewAtom() *Atom {
+	return d.level.NewAtom()
+}
+
+/*
+	Returns the UnderlyingWriteBatch of the Atom.
+*/
+func (a *Atom) Inner() UnderlyingWriteBatch {
+	return a.UnderlyingWriteBatch
+}
+
 /*
 	Empty the writes and deletes of this Atom.
 */
@@ -34,3 +54,102 @@ func (a *Atom) Put(k Key, v Value) *Atom {
 	a.UnderlyingWriteBatch.Put(k, v)
 	return a
 }
+
+/*
+	Returns the number of operations in the Atom.
+*/
+func (a *Atom) Len() int {
+	return a.UnderlyingWriteBatch.Len()
+}
+
+/*
+	Function Replay gives each operation of the Atom to r, in the order
+	in which they were added.
+*/
+func (a *Atom) Replay(r AtomReplayer) error {
+	return a.UnderlyingWriteBatch.Replay(r)
+}
+
+/*
+	Function ByteSize returns the size of the Atom in LevelDB's
+	write batch format, which is how much it adds to the log when written.
+*/
+func (a *Atom) ByteSize() (BytesSize, error) {
+	var s sizer
+	if err := a.Replay(&s); err != nil {
+		return 0, err
+	}
+	return batchHeaderLen + s.n, nil
+}
+
+/*
+	Renders the operations of the Atom, as in
+
+		Atom{Put("a", "1"), Delete("b")}
+*/
+func (a *Atom) String() string {
+	var p printer
+	if err := a.Replay(&p); err != nil {
+		return fmt.Sprintf("Atom{%v}", err)
+	}
+	return "Atom{" + strings.Join(p, ", ") + "}"
+}
+
+//A LevelDB write batch begins with a sequence number and a count.
+const batchHeaderLen = 12
+
+//sizer counts the bytes which operations take in a write batch,
+//each being a tag followed by a length-prefixed key, and value for Puts.
+type sizer struct {
+	n BytesSize
+}
+
+func (s *sizer) Put(k Key, v Value) {
+	s.n += 1 + uvarintLen(len(k)) + BytesSize(len(k)) + uvarintLen(len(v)) + BytesSize(len(v))
+}
+
+func (s *sizer) Delete(k Key) {
+	s.n += 1 + uvarintLen(len(k)) + BytesSize(len(k))
+}
+
+func uvarintLen(n int) BytesSize {
+	var buf [binary.MaxVarintLen64]byte
+	return BytesSize(binary.PutUvarint(buf[:], uint64(n)))
+}
+
+type printer []string
+
+func (p *printer) Put(k Key, v Value) {
+	*p = append(*p, fmt.Sprintf("Put(%q, %q)", k, v))
+}
+
+func (p *printer) Delete(k Key) {
+	*p = append(*p, fmt.Sprintf("Delete(%q)", k))
+}
+
+type recorded struct {
+	del bool
+	k   Key
+	v   Value
+}
+
+//recording holds the operations of an Atom, in order.
+type recording []recorded
+
+func (r *recording) Put(k Key, v Value) {
+	*r = append(*r, recorded{false, k, v})
+}
+
+func (r *recording) Delete(k Key) {
+	*r = append(*r, recorded{true, k, nil})
+}
+
+func (r recording) replay(to AtomReplayer) {
+	for _, o := range r {
+		if o.del {
+			to.Delete(o.k)
+		} else {
+			to.Put(o.k, o.v)
+		}
+	}
+}
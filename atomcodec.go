@@ -0,0 +1,118 @@
+package level
+
+import (
+	"encoding/binary"
+)
+
+//Tags of the operations in a LevelDB write batch.
+const (
+	tagDelete byte = iota
+	tagPut
+)
+
+/*
+	Function MarshalBinary encodes the Atom in LevelDB's write batch format:
+	an eight byte sequence number, which is zero, and a four byte count,
+	both little-endian, followed by each operation as a tag byte and
+	varint length-prefixed Key, and Value for Puts.
+
+	The encoding does not depend on the implementation which made the Atom,
+	so an Atom made by one may be unmarshaled and written by another.
+*/
+func (a *Atom) MarshalBinary() ([]byte, error) {
+	if d, ok := a.UnderlyingWriteBatch.(UnderlyingWriteBatchDumper); ok {
+		data := append([]byte(nil), d.Dump()...)
+		if len(data) < batchHeaderLen {
+			//An empty batch may not have been given its header.
+			return make([]byte, batchHeaderLen), nil
+		}
+		//A batch which has been written carries its sequence number.
+		binary.LittleEndian.PutUint64(data, 0)
+		return data, nil
+	}
+
+	e := encoder(make([]byte, batchHeaderLen))
+	if err := a.Replay(&e); err != nil {
+		return nil, err
+	}
+	binary.LittleEndian.PutUint32(e[8:batchHeaderLen], uint32(a.Len()))
+	return e, nil
+}
+
+/*
+	Function UnmarshalBinary replaces the operations of the Atom with those
+	encoded in data by MarshalBinary. The Atom must have been made by a Level.
+	If data is corrupt, ErrCorruptAtom is returned and the Atom is left empty.
+*/
+func (a *Atom) UnmarshalBinary(data []byte) error {
+	a.Clear()
+	//Every record is checked before any is added to the Atom.
+	return decode(data, a.UnderlyingWriteBatch)
+}
+
+type encoder []byte
+
+func (e *encoder) slice(b []byte) {
+	var buf [binary.MaxVarintLen64]byte
+	*e = append(append(*e, buf[:binary.PutUvarint(buf[:], uint64(len(b)))]...), b...)
+}
+
+func (e *encoder) Put(k Key, v Value) {
+	*e = append(*e, tagPut)
+	e.slice(k)
+	e.slice(v)
+}
+
+func (e *encoder) Delete(k Key) {
+	*e = append(*e, tagDelete)
+	e.slice(k)
+}
+
+//decode gives the operations encoded in data to r,
+//after checking that all of them are well formed.
+func decode(data []byte, r AtomReplayer) error {
+	if len(data) < batchHeaderLen {
+		return ErrCorruptAtom
+	}
+	count := binary.LittleEndian.Uint32(data[8:batchHeaderLen])
+
+	var ops recording
+	for rest := data[batchHeaderLen:]; len(rest) > 0; {
+		var o recorded
+		tag := rest[0]
+		rest = rest[1:]
+		switch tag {
+		case tagDelete:
+			o.del = true
+		case tagPut:
+		default:
+			return ErrCorruptAtom
+		}
+
+		var ok bool
+		if o.k, rest, ok = slice(rest); !ok {
+			return ErrCorruptAtom
+		}
+		if !o.del {
+			if o.v, rest, ok = slice(rest); !ok {
+				return ErrCorruptAtom
+			}
+		}
+		ops = append(ops, o)
+	}
+	if uint32(len(ops)) != count {
+		return ErrCorruptAtom
+	}
+
+	ops.replay(r)
+	return nil
+}
+
+//slice reads a varint length-prefixed slice from the front of b.
+func slice(b []byte) (s, rest []byte, ok bool) {
+	n, l := binary.Uvarint(b)
+	if l <= 0 || uint64(len(b)-l) < n {
+		return nil, nil, false
+	}
+	return b[l : l+int(n)], b[l+int(n):], true
+}
@@ -5,3 +5,14 @@ func (l *Level) NewCache(capacity BytesSize) *Cache {
 		l.NewLRUCache(int(capacity)),
 	}
 }
+
+/*
+	Function NewBloomFilter returns a FilterPolicy which keeps a bloom filter
+	of the Keys in each block, using about bitsPerKey bits for each.
+	A FilterPolicy must not be closed before the Databases using it.
+*/
+func (l *Level) NewBloomFilter(bitsPerKey int) *FilterPolicy {
+	return &FilterPolicy{
+		l.UnderlyingLevel.NewBloomFilter(bitsPerKey),
+	}
+}
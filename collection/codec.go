@@ -0,0 +1,174 @@
+package collection
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+)
+
+/*
+	A Codec encodes values of type T as bytes, and decodes them again.
+	Codecs used for Keys must preserve order: if a sorts before b,
+	the encoding of a sorts bytewise before that of b.
+*/
+type Codec[T any] interface {
+	Encode(T) ([]byte, error)
+	Decode([]byte) (T, error)
+}
+
+/*
+	ErrLength is returned when decoding data of the wrong length
+	for its Codec.
+*/
+var ErrLength = errors.New("collection: encoded value has the wrong length")
+
+//funcs makes a Codec of a pair of functions.
+type funcs[T any] struct {
+	encode func(T) ([]byte, error)
+	decode func([]byte) (T, error)
+}
+
+func (f funcs[T]) Encode(v T) ([]byte, error) {
+	return f.encode(v)
+}
+
+func (f funcs[T]) Decode(b []byte) (T, error) {
+	return f.decode(b)
+}
+
+type (
+	signed interface {
+		~int | ~int8 | ~int16 | ~int32 | ~int64
+	}
+	unsigned interface {
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+	}
+)
+
+/*
+	Function Uint64 returns an order-preserving Codec for unsigned integers,
+	which encodes them as eight big-endian bytes.
+*/
+func Uint64[T unsigned]() Codec[T] {
+	return funcs[T]{
+		func(v T) ([]byte, error) {
+			b := make([]byte, 8)
+			binary.BigEndian.PutUint64(b, uint64(v))
+			return b, nil
+		},
+		func(b []byte) (T, error) {
+			if len(b) != 8 {
+				return 0, ErrLength
+			}
+			return T(binary.BigEndian.Uint64(b)), nil
+		},
+	}
+}
+
+const signBit = 1 << 63
+
+/*
+	Function Int64 returns an order-preserving Codec for signed integers,
+	which encodes them as eight big-endian bytes with the sign bit flipped,
+	so that negative numbers sort before positive ones.
+*/
+func Int64[T signed]() Codec[T] {
+	return funcs[T]{
+		func(v T) ([]byte, error) {
+			b := make([]byte, 8)
+			binary.BigEndian.PutUint64(b, uint64(v)^signBit)
+			return b, nil
+		},
+		func(b []byte) (T, error) {
+			if len(b) != 8 {
+				return 0, ErrLength
+			}
+			return T(int64(binary.BigEndian.Uint64(b) ^ signBit)), nil
+		},
+	}
+}
+
+/*
+	Function String returns an order-preserving Codec for strings,
+	which encodes them as their bytes.
+*/
+func String[T ~string]() Codec[T] {
+	return funcs[T]{
+		func(v T) ([]byte, error) {
+			return []byte(v), nil
+		},
+		func(b []byte) (T, error) {
+			return T(b), nil
+		},
+	}
+}
+
+/*
+	Function Bytes returns an order-preserving Codec for byte slices,
+	which copies them.
+*/
+func Bytes[T ~[]byte]() Codec[T] {
+	return funcs[T]{
+		func(v T) ([]byte, error) {
+			return append([]byte{}, v...), nil
+		},
+		func(b []byte) (T, error) {
+			return append(T{}, b...), nil
+		},
+	}
+}
+
+/*
+	ErrTimeRange is returned when encoding a Time which cannot be held
+	as nanoseconds since the Unix epoch.
+*/
+var ErrTimeRange = errors.New("collection: time out of range")
+
+//The least and greatest Times which Time can encode.
+var (
+	minTime = time.Unix(0, math.MinInt64)
+	maxTime = time.Unix(0, math.MaxInt64)
+)
+
+/*
+	Function Time returns an order-preserving Codec for times, which encodes
+	them as nanoseconds since the Unix epoch, as Int64 does. Times are
+	decoded in UTC. Times outside those which nanoseconds since the epoch
+	can hold, from 1677 to 2262, including the zero Time, give ErrTimeRange.
+*/
+func Time() Codec[time.Time] {
+	ints := Int64[int64]()
+	return funcs[time.Time]{
+		func(t time.Time) ([]byte, error) {
+			if t.Before(minTime) || t.After(maxTime) {
+				return nil, ErrTimeRange
+			}
+			return ints.Encode(t.UnixNano())
+		},
+		func(b []byte) (time.Time, error) {
+			n, err := ints.Decode(b)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(0, n).UTC(), nil
+		},
+	}
+}
+
+/*
+	Function JSON returns a Codec which encodes values as JSON.
+	It does not preserve order, and so is for Values rather than Keys.
+*/
+func JSON[T any]() Codec[T] {
+	return funcs[T]{
+		func(v T) ([]byte, error) {
+			return json.Marshal(v)
+		},
+		func(b []byte) (v T, err error) {
+			err = json.Unmarshal(b, &v)
+			return
+		},
+	}
+}
@@ -0,0 +1,214 @@
+/*
+	Package collection provides typed collections of Keys and Values,
+	stored under a Key prefix of a *level.Database.
+
+		users := collection.New(
+			db,
+			level.Key("users/"),
+			collection.Uint64[uint64](),
+			collection.JSON[User](),
+		)
+
+		if err := users.Put(42, User{Name: "Ada"}); err != nil {
+			return err
+		}
+
+		u, err := users.Get(42)
+
+	Collections are ordered by the encoding of their Keys, so the key codecs
+	given here preserve the order of what they encode under the Database's
+	default, bytewise, comparator. The prefixes of two collections in one
+	Database must not be prefixes of one another.
+*/
+package collection
+
+import (
+	"errors"
+	"github.com/TShadwell/level"
+)
+
+/*
+	Stop may be returned by the function given to Range to end it early,
+	without Range returning an error.
+*/
+var Stop = errors.New("collection: stop")
+
+/*
+	A Collection holds Values of type V at Keys of type K, which are stored
+	under a prefix, encoded by their Codecs.
+*/
+type Collection[K, V any] struct {
+	db     *level.Database
+	prefix level.Key
+	keys   Codec[K]
+	values Codec[V]
+}
+
+/*
+	Function New returns the Collection of d under prefix,
+	whose Keys and Values are encoded by keys and values.
+*/
+func New[K, V any](d *level.Database, prefix level.Key, keys Codec[K], values Codec[V]) *Collection[K, V] {
+	return &Collection[K, V]{
+		db:     d,
+		prefix: append(level.Key{}, prefix...),
+		keys:   keys,
+		values: values,
+	}
+}
+
+//key returns the Key at which k is stored.
+func (c *Collection[K, V]) key(k K) (level.Key, error) {
+	b, err := c.keys.Encode(k)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(level.Key{}, c.prefix...), b...), nil
+}
+
+/*
+	Function Get returns the Value stored at k. If there is none,
+	the error is level.ErrNotFound.
+*/
+func (c *Collection[K, V]) Get(k K) (v V, err error) {
+	key, err := c.key(k)
+	if err != nil {
+		return
+	}
+	b, err := c.db.Get(key)
+	if err != nil {
+		return
+	}
+	return c.values.Decode(b)
+}
+
+/*
+	Function Put stores v at k.
+*/
+func (c *Collection[K, V]) Put(k K, v V) error {
+	key, value, err := c.encode(k, v)
+	if err != nil {
+		return err
+	}
+	return c.db.Put(key, value)
+}
+
+/*
+	Function Delete removes the Value stored at k.
+*/
+func (c *Collection[K, V]) Delete(k K) error {
+	key, err := c.key(k)
+	if err != nil {
+		return err
+	}
+	return c.db.Delete(key)
+}
+
+/*
+	Function PutIn adds storing v at k to the Atom, so that writes
+	to many collections may be made together.
+*/
+func (c *Collection[K, V]) PutIn(a *level.Atom, k K, v V) error {
+	key, value, err := c.encode(k, v)
+	if err != nil {
+		return err
+	}
+	a.Put(key, value)
+	return nil
+}
+
+/*
+	Function DeleteIn adds removing the Value stored at k to the Atom.
+*/
+func (c *Collection[K, V]) DeleteIn(a *level.Atom, k K) error {
+	key, err := c.key(k)
+	if err != nil {
+		return err
+	}
+	a.Delete(key)
+	return nil
+}
+
+func (c *Collection[K, V]) encode(k K, v V) (level.Key, level.Value, error) {
+	key, err := c.key(k)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := c.values.Encode(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, value, nil
+}
+
+/*
+	Function Range calls f with each Key and Value from start up to but not
+	including limit, in order. A nil start or limit leaves that end of the
+	range unbounded, so that
+
+		c.Range(nil, nil, f)
+
+	visits the whole Collection. If f returns an error, Range stops and
+	returns it, unless it is Stop.
+*/
+func (c *Collection[K, V]) Range(start, limit *K, f func(K, V) error) (err error) {
+	from, to := c.prefix, level.PrefixLimit(c.prefix)
+	if start != nil {
+		if from, err = c.key(*start); err != nil {
+			return
+		}
+	}
+	if limit != nil {
+		if to, err = c.key(*limit); err != nil {
+			return
+		}
+	}
+
+	it := c.db.NewIterator()
+	defer it.Close()
+
+	for it.Seek(from); it.Valid(); it.Next() {
+		key := it.Key()
+		if to != nil && level.BytewiseComparator.Compare(key, to) >= 0 {
+			break
+		}
+
+		var (
+			k K
+			v V
+		)
+		if k, err = c.keys.Decode(key[len(c.prefix):]); err != nil {
+			return
+		}
+		if v, err = c.values.Decode(it.Value()); err != nil {
+			return
+		}
+		if err = f(k, v); err != nil {
+			if err == Stop {
+				err = nil
+			}
+			return
+		}
+	}
+	return it.Err()
+}
+
+/*
+	Function Len returns the number of Values in the Collection,
+	which it counts by iterating over them.
+*/
+func (c *Collection[K, V]) Len() (n int, err error) {
+	limit := level.PrefixLimit(c.prefix)
+
+	it := c.db.NewIterator()
+	defer it.Close()
+
+	for it.Seek(c.prefix); it.Valid(); it.Next() {
+		if limit != nil && level.BytewiseComparator.Compare(it.Key(), limit) >= 0 {
+			break
+		}
+		n++
+	}
+	err = it.Err()
+	return
+}
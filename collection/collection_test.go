@@ -0,0 +1,145 @@
+package collection
+
+import (
+	"bytes"
+	"github.com/TShadwell/level"
+	"github.com/TShadwell/level/leveltest"
+	"github.com/TShadwell/level/memlevel"
+	"math"
+	"sort"
+	"testing"
+	"time"
+)
+
+type user struct {
+	Name string
+	Age  int
+}
+
+func open(t *testing.T) *level.Database {
+	return leveltest.Open(t, memlevel.Level, t.Name())
+}
+
+func TestCollection(t *testing.T) {
+	db := open(t)
+
+	users := New(db, level.Key("users/"), Int64[int](), JSON[user]())
+	names := New(db, level.Key("names/"), String[string](), Int64[int]())
+
+	if _, err := users.Get(1); err != level.ErrNotFound {
+		t.Fatal("Expected ErrNotFound for a missing user, got: ", err)
+	}
+
+	for i, name := range []string{"Ada", "Brian", "Claude", "Dennis"} {
+		an := memlevel.Level.NewAtom()
+		if err := users.PutIn(an, i-1, user{name, 30 + i}); err != nil {
+			t.Fatal(err)
+		}
+		if err := names.PutIn(an, name, i-1); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Commit(an); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	u, err := users.Get(1)
+	if err != nil || u.Name != "Claude" {
+		t.Fatalf("User 1 is %v, %v", u, err)
+	}
+	if n, err := users.Len(); err != nil || n != 4 {
+		t.Fatalf("There are %d users, expected 4: %v", n, err)
+	}
+
+	var seen []int
+	err = users.Range(nil, nil, func(k int, u user) error {
+		seen = append(seen, k)
+		return nil
+	})
+	if err != nil || !sort.IntsAreSorted(seen) || len(seen) != 4 || seen[0] != -1 {
+		t.Fatalf("Ranged over %v, %v", seen, err)
+	}
+
+	start, limit := 0, 2
+	seen = nil
+	err = users.Range(&start, &limit, func(k int, u user) error {
+		seen = append(seen, k)
+		return nil
+	})
+	if err != nil || len(seen) != 2 || seen[0] != 0 || seen[1] != 1 {
+		t.Fatalf("Ranged over %v from 0 to 2, %v", seen, err)
+	}
+
+	seen = nil
+	err = names.Range(nil, nil, func(k string, v int) error {
+		seen = append(seen, v)
+		return Stop
+	})
+	if err != nil || len(seen) != 1 || seen[0] != -1 {
+		t.Fatalf("Stopped range saw %v, %v", seen, err)
+	}
+
+	an := memlevel.Level.NewAtom()
+	if err = users.DeleteIn(an, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err = names.DeleteIn(an, "Ada"); err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+	if err = users.Delete(0); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := users.Len(); err != nil || n != 2 {
+		t.Fatalf("There are %d users, expected 2: %v", n, err)
+	}
+	if n, err := names.Len(); err != nil || n != 3 {
+		t.Fatalf("There are %d names, expected 3: %v", n, err)
+	}
+}
+
+func ordered[T any](t *testing.T, c Codec[T], values ...T) {
+	var last []byte
+	for i, v := range values {
+		b, err := c.Encode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && bytes.Compare(last, b) >= 0 {
+			t.Fatalf("Encoding of %v does not sort after that of %v", v, values[i-1])
+		}
+		last = b
+
+		d, err := c.Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e, _ := c.Encode(d); !bytes.Equal(e, b) {
+			t.Fatalf("%v decoded as %v", v, d)
+		}
+	}
+}
+
+func TestKeyCodecs(t *testing.T) {
+	ordered(t, Int64[int64](), math.MinInt64, -256, -1, 0, 1, 255, math.MaxInt64)
+	ordered(t, Int64[int8](), math.MinInt8, -1, 0, 1, math.MaxInt8)
+	ordered(t, Uint64[uint64](), 0, 1, 255, 256, math.MaxUint64)
+	ordered(t, String[string](), "", "a", "aa", "b")
+	ordered(t, Bytes[[]byte](), []byte{}, []byte{0}, []byte{0xff})
+
+	epoch := time.Unix(0, 0).UTC()
+	ordered(t, Time(), epoch.Add(-time.Hour), epoch, epoch.Add(time.Nanosecond), time.Now())
+
+	for _, out := range []time.Time{{}, epoch.AddDate(-300, 0, 0), epoch.AddDate(300, 0, 0)} {
+		if _, err := Time().Encode(out); err != ErrTimeRange {
+			t.Fatalf("Expected ErrTimeRange encoding %v, got: %v", out, err)
+		}
+	}
+	ordered(t, Time(), time.Unix(0, math.MinInt64), time.Unix(0, math.MaxInt64))
+
+	if _, err := Int64[int]().Decode([]byte{1}); err != ErrLength {
+		t.Fatal("Expected ErrLength for a short integer, got: ", err)
+	}
+}
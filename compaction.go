@@ -0,0 +1,35 @@
+package level
+
+/*
+	Function CompactRange compacts the Keys from start up to limit,
+	discarding deleted and overwritten values and reclaiming their space.
+	A nil start or limit leaves that end of the range unbounded, so
+	CompactRange(nil, nil) compacts the whole Database.
+*/
+func (d *Database) CompactRange(start, limit Key) error {
+	return d.UnderlyingDatabase.CompactRange(start, limit)
+}
+
+/*
+	Function SuspendCompaction pauses background compaction, such as
+	for the duration of a bulk load, until ResumeCompaction is called.
+	If the implementation cannot do so, ErrCompactionUnsupported is returned.
+*/
+func (d *Database) SuspendCompaction() error {
+	if s, ok := d.UnderlyingDatabase.(UnderlyingCompactionSuspender); ok {
+		return s.SuspendCompaction()
+	}
+	return ErrCompactionUnsupported
+}
+
+/*
+	Function ResumeCompaction resumes background compaction after
+	SuspendCompaction.
+	If the implementation cannot do so, ErrCompactionUnsupported is returned.
+*/
+func (d *Database) ResumeCompaction() error {
+	if s, ok := d.UnderlyingDatabase.(UnderlyingCompactionSuspender); ok {
+		return s.ResumeCompaction()
+	}
+	return ErrCompactionUnsupported
+}
@@ -0,0 +1,80 @@
+package level
+
+import (
+	"bytes"
+)
+
+/*
+	BytewiseComparator orders Keys lexicographically by their bytes.
+	It is the Comparator used when none is set, and the only one which
+	every implementation supports.
+*/
+var BytewiseComparator ComparatorShortener = bytewise{}
+
+type bytewise struct{}
+
+func (bytewise) Compare(a, b Key) int {
+	return bytes.Compare(a, b)
+}
+
+//The name LevelDB gives its own bytewise comparator, so that databases
+//may be shared with other LevelDB programs.
+func (bytewise) Name() string {
+	return "leveldb.BytewiseComparator"
+}
+
+func (bytewise) Separator(start, limit Key) Key {
+	//Find the length of the common prefix.
+	n := len(start)
+	if len(limit) < n {
+		n = len(limit)
+	}
+	var i int
+	for i < n && start[i] == limit[i] {
+		i++
+	}
+
+	//Do not shorten if one is a prefix of the other.
+	if i < n {
+		if c := start[i]; c < 0xff && c+1 < limit[i] {
+			k := append(Key{}, start[:i+1]...)
+			k[i]++
+			return k
+		}
+	}
+	return start
+}
+
+func (bytewise) Successor(k Key) Key {
+	//Find the first byte which may be incremented.
+	for i, c := range k {
+		if c != 0xff {
+			s := append(Key{}, k[:i+1]...)
+			s[i]++
+			return s
+		}
+	}
+	//k is a run of 0xffs, so leave it alone.
+	return k
+}
+
+/*
+	Function SetComparator sets the ordering of Keys in the UnderlyingDatabase.
+	A database must always be opened with a Comparator of the same Name;
+	opening it with another fails with a *ComparatorMismatchError.
+	Implementations which cannot use a Comparator other than
+	BytewiseComparator fail to open with ErrComparatorUnsupported.
+*/
+func (o *Options) SetComparator(c Comparator) *Options {
+	o.UnderlyingOptions.SetComparator(c)
+	o.comparator = c
+	return o
+}
+
+//Comparator returns the Comparator set on the Options, or BytewiseComparator.
+func (o *Options) Comparator() Comparator {
+	if o == nil || o.comparator == nil {
+		return BytewiseComparator
+	}
+	return o.comparator
+}
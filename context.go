@@ -0,0 +1,297 @@
+package level
+
+import (
+	"context"
+)
+
+/*
+	The Context variants of the Database's functions return ctx.Err() as soon
+	as ctx is done. The underlying implementations cannot be interrupted, so
+	an operation which has begun carries on in the background; a write may
+	still be made after its Context variant has returned. At most
+	ContextWorkers such operations run at once for each Database; beyond
+	that, a Context variant waits for one to finish, or for ctx to be done.
+	Close waits for such operations to finish. The Keys, Values and Atoms given are copied
+	first, so that they may be reused as soon as the call returns.
+*/
+
+//do runs f, unless ctx is already done, returning early if ctx is done first.
+func (d *Database) do(ctx context.Context, f func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.start(ctx, f)
+}
+
+//start runs f, returning early if ctx is done first.
+func (d *Database) start(ctx context.Context, f func() error) error {
+	if ctx.Done() == nil {
+		return f()
+	}
+
+	d.workersOnce.Do(func() {
+		d.workers = make(chan struct{}, ContextWorkers)
+	})
+	select {
+	case d.workers <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	d.inflight.Add(1)
+	done := make(chan error, 1)
+	go func() {
+		defer d.inflight.Done()
+		defer func() { <-d.workers }()
+		done <- f()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+	ContextWorkers is how many operations from Context variants
+	a Database runs at once. It is read when a Database first runs one.
+*/
+var ContextWorkers = 64
+
+//detach copies b if ctx may be done, as an operation given b
+//may then outlive the call which the caller gave it to.
+func detach(ctx context.Context, b []byte) []byte {
+	if ctx.Done() == nil {
+		return b
+	}
+	return append([]byte(nil), b...)
+}
+
+/*
+	Function GetContext gets a single value, as Get does,
+	unless ctx is done first.
+*/
+func (d *Database) GetContext(ctx context.Context, k Key) (Value, error) {
+	return d.getContext(ctx, nil, k)
+}
+
+func (d *Database) getContext(ctx context.Context, r *ReadOptions, k Key) (Value, error) {
+	var v Value
+	k = detach(ctx, k)
+	err := d.do(ctx, func() (err error) {
+		v, err = d.get(d.readOptions(r), k)
+		return
+	})
+	//If ctx is done first, v may yet be set, and must not be read.
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+/*
+	Function HasContext reports whether a Value is stored at Key, as Has does,
+	unless ctx is done first.
+*/
+func (d *Database) HasContext(ctx context.Context, k Key) (bool, error) {
+	var has bool
+	k = detach(ctx, k)
+	err := d.do(ctx, func() (err error) {
+		has, err = d.HasWith(nil, k)
+		return
+	})
+	if err != nil {
+		return false, err
+	}
+	return has, nil
+}
+
+/*
+	Function PutContext puts a single value, as Put does,
+	unless ctx is done first.
+	If ctx is done first, the write may still be applied.
+*/
+func (d *Database) PutContext(ctx context.Context, k Key, v Value) error {
+	k, v = detach(ctx, k), detach(ctx, v)
+	return d.do(ctx, func() error {
+		return d.PutWith(nil, k, v)
+	})
+}
+
+/*
+	Function DeleteContext deletes a single value, as Delete does,
+	unless ctx is done first.
+	If ctx is done first, the write may still be applied.
+*/
+func (d *Database) DeleteContext(ctx context.Context, k Key) error {
+	k = detach(ctx, k)
+	return d.do(ctx, func() error {
+		return d.DeleteWith(nil, k)
+	})
+}
+
+/*
+	Function WriteContext writes an Atom or InterfaceAtom, as Write does,
+	unless ctx is done first. If ctx is done first, the write may still be
+	applied. The Atom may be reused once WriteContext returns, even if its
+	write carries on.
+*/
+func (d *Database) WriteContext(ctx context.Context, an atom) error {
+	return d.writeContext(ctx, an, false)
+}
+
+/*
+	Function CommitContext writes an Atom or InterfaceAtom as WriteContext
+	does, closing it afterward. If ctx is done first, the write may still
+	be applied.
+*/
+func (d *Database) CommitContext(ctx context.Context, an atom) error {
+	return d.writeContext(ctx, an, true)
+}
+
+func (d *Database) writeContext(ctx context.Context, an atom, close bool) error {
+	if close {
+		defer an.Inner().Close()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Done() == nil {
+		return d.WriteWith(nil, an)
+	}
+
+	//The write may outlive this call, so it is made from a copy.
+	own := d.level.NewAtom()
+	if err := an.Inner().Replay(own.UnderlyingWriteBatch); err != nil {
+		own.Close()
+		return err
+	}
+	return d.start(ctx, func() error {
+		defer own.Close()
+		return d.WriteWith(nil, own)
+	})
+}
+
+/*
+	Function NewIteratorContext returns an Iterator over the Database, as
+	NewIterator does, which becomes invalid once ctx is done, with Err
+	returning ctx.Err(). It checks ctx at each Seek, and every
+	IteratorContextChunk moves.
+*/
+func (d *Database) NewIteratorContext(ctx context.Context) *Iterator {
+	it := d.NewIteratorWith(nil)
+	if ctx.Done() == nil {
+		return it
+	}
+	it.UnderlyingIterator = &ctxIterator{
+		UnderlyingIterator: it.UnderlyingIterator,
+		ctx:                ctx,
+	}
+	return it
+}
+
+/*
+	IteratorContextChunk is how many moves an Iterator from
+	NewIteratorContext makes between checks of its Context.
+*/
+var IteratorContextChunk = 256
+
+type ctxIterator struct {
+	UnderlyingIterator
+	ctx   context.Context
+	moves int
+	err   error
+}
+
+func (c *ctxIterator) check() {
+	if c.err == nil {
+		c.err = c.ctx.Err()
+	}
+}
+
+func (c *ctxIterator) moved() {
+	if c.moves++; c.moves >= IteratorContextChunk {
+		c.moves = 0
+		c.check()
+	}
+}
+
+func (c *ctxIterator) Seek(k Key) {
+	c.check()
+	c.UnderlyingIterator.Seek(k)
+}
+
+func (c *ctxIterator) SeekToFirst() {
+	c.check()
+	c.UnderlyingIterator.SeekToFirst()
+}
+
+func (c *ctxIterator) SeekToLast() {
+	c.check()
+	c.UnderlyingIterator.SeekToLast()
+}
+
+func (c *ctxIterator) Next() {
+	c.moved()
+	c.UnderlyingIterator.Next()
+}
+
+func (c *ctxIterator) Prev() {
+	c.moved()
+	c.UnderlyingIterator.Prev()
+}
+
+func (c *ctxIterator) Valid() bool {
+	return c.err == nil && c.UnderlyingIterator.Valid()
+}
+
+func (c *ctxIterator) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.UnderlyingIterator.Err()
+}
+
+/*
+	Function DeleteRangeContext deletes a range of Keys as DeleteRange does,
+	stopping between chunks once ctx is done. The chunk being written when
+	ctx is done may still be applied. An UnderlyingRangeDeleter's native
+	DeleteRange cannot be interrupted: ctx is checked only before it begins,
+	and it then deletes the whole range.
+*/
+func (d *Database) DeleteRangeContext(ctx context.Context, start, limit Key) (n int, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if r, ok := d.UnderlyingDatabase.(UnderlyingRangeDeleter); ok {
+		return r.DeleteRange(d.WriteOptions.UnderlyingWriteOptions, start, limit)
+	}
+
+	it := d.NewIteratorContext(ctx)
+	defer it.Close()
+
+	an := d.level.NewAtom()
+	defer an.Close()
+
+	var pending int
+	for it.Seek(start); it.Valid() && d.before(it.Key(), limit); it.Next() {
+		an.Delete(it.Key())
+		if pending++; pending < DeleteRangeChunk {
+			continue
+		}
+		if err = d.WriteContext(ctx, an); err != nil {
+			return
+		}
+		n, pending = n+pending, 0
+		an.Clear()
+	}
+	if err = it.Err(); err != nil || pending == 0 {
+		return
+	}
+	if err = d.WriteContext(ctx, an); err == nil {
+		n += pending
+	}
+	return
+}
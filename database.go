@@ -1,10 +1,22 @@
 package level
 
+import (
+	"context"
+)
+
 func (l *Level) OpenDatabase(d *Database, location string) (err error) {
 	if d.Options == nil {
 		d.Options = l.NewOptions()
 	}
 
+	if d.Cache != nil {
+		d.Options.SetCache(d.Cache)
+	}
+
+	if d.FilterPolicy != nil {
+		d.Options.SetFilterPolicy(d.FilterPolicy)
+	}
+
 	if d.ReadOptions == nil {
 		d.ReadOptions = l.NewReadOptions()
 	}
@@ -12,6 +24,7 @@ func (l *Level) OpenDatabase(d *Database, location string) (err error) {
 	if d.WriteOptions == nil {
 		d.WriteOptions = l.NewWriteOptions()
 	}
+	d.level = l
 	d.UnderlyingDatabase, err = l.UnderlyingLevel.OpenDatabase(location, d.Options.UnderlyingOptions)
 	if err != nil {
 		return
@@ -19,12 +32,54 @@ func (l *Level) OpenDatabase(d *Database, location string) (err error) {
 	return
 }
 
-func (d *Database) Close() {
-	d.UnderlyingDatabase.Close()
-	d.Cache.Close()
-	d.Options.Close()
-	d.ReadOptions.Close()
-	d.WriteOptions.Close()
+/*
+	Function DestroyDatabase removes the database at location, and all its contents.
+	If o is nil, default Options are used.
+*/
+func (l *Level) DestroyDatabase(location string, o *Options) error {
+	if o == nil {
+		o = l.NewOptions()
+		defer o.Close()
+	}
+	return l.UnderlyingLevel.DestroyDatabase(location, o.UnderlyingOptions)
+}
+
+/*
+	Function RepairDatabase attempts to recover as much data as possible
+	from a corrupted database at location.
+	If o is nil, default Options are used.
+*/
+func (l *Level) RepairDatabase(location string, o *Options) error {
+	if o == nil {
+		o = l.NewOptions()
+		defer o.Close()
+	}
+	return l.UnderlyingLevel.RepairDatabase(location, o.UnderlyingOptions)
+}
+
+/*
+	Function Close closes the Database, its Cache, FilterPolicy and options,
+	once any operations left running by Context variants, and any group
+	still gathering writes for group commit, have finished.
+	Every part is closed even if an earlier one fails; all failures
+	are returned together as Errors.
+*/
+func (d *Database) Close() error {
+	if d.group != nil {
+		d.group.drain()
+	}
+	d.inflight.Wait()
+
+	var errs Errors
+	if d.UnderlyingDatabase != nil {
+		errs = errs.Append(d.UnderlyingDatabase.Close())
+	}
+	errs = errs.Append(d.Cache.Close())
+	errs = errs.Append(d.FilterPolicy.Close())
+	errs = errs.Append(d.Options.Close())
+	errs = errs.Append(d.ReadOptions.Close())
+	errs = errs.Append(d.WriteOptions.Close())
+	return errs.Err()
 }
 
 /*
@@ -52,7 +107,20 @@ func (d *Database) Inner() UnderlyingDatabase {
 	For batch deletions, use an Atom.
 */
 func (d *Database) Delete(k Key) error {
-	return d.UnderlyingDatabase.Delete(d.WriteOptions.UnderlyingWriteOptions, k)
+	return d.DeleteContext(context.Background(), k)
+}
+
+/*
+	Function DeleteWith deletes a single value, using w in place of
+	the Database's WriteOptions. If w is nil, the Database's are used.
+*/
+func (d *Database) DeleteWith(w *WriteOptions, k Key) error {
+	if d.grouped(w) {
+		return d.group.commit(func(a AtomReplayer) {
+			a.Delete(k)
+		})
+	}
+	return d.UnderlyingDatabase.Delete(d.writeOptions(w), k)
 }
 
 /*
@@ -60,28 +128,135 @@ func (d *Database) Delete(k Key) error {
 	For batch puts, use an Atom.
 */
 func (d *Database) Put(k Key, v Value) error {
-	return d.UnderlyingDatabase.Put(d.WriteOptions.UnderlyingWriteOptions, k, v)
+	return d.PutContext(context.Background(), k, v)
+}
+
+/*
+	Function PutWith puts a single value, using w in place of
+	the Database's WriteOptions. If w is nil, the Database's are used.
+*/
+func (d *Database) PutWith(w *WriteOptions, k Key, v Value) error {
+	if d.grouped(w) {
+		return d.group.commit(func(a AtomReplayer) {
+			a.Put(k, v)
+		})
+	}
+	return d.UnderlyingDatabase.Put(d.writeOptions(w), k, v)
+}
+
+//grouped reports whether writes with w go through group commit,
+//which only writes with the Database's own WriteOptions.
+func (d *Database) grouped(w *WriteOptions) bool {
+	return d.group != nil && (w == nil || w == d.WriteOptions)
+}
+
+func (d *Database) writeOptions(w *WriteOptions) UnderlyingWriteOptions {
+	if w == nil {
+		w = d.WriteOptions
+	}
+	return w.UnderlyingWriteOptions
+}
+
+func (d *Database) readOptions(r *ReadOptions) *ReadOptions {
+	if r == nil {
+		return d.ReadOptions
+	}
+	return r
+}
+
+/*
+	Function SetNilOnNotFound enables a compatibility mode in which
+	Gets of a missing Key return a nil Value and a nil error,
+	rather than ErrNotFound. This was the behaviour of previous versions.
+*/
+func (d *Database) SetNilOnNotFound(yes bool) *Database {
+	d.nilNotFound = yes
+	return d
 }
 
 /*
 	Gets a single value from the UnderlyingDatabase.
+	If the Key is not present, ErrNotFound is returned.
 */
 func (d *Database) Get(k Key) (Value, error) {
-	return d.UnderlyingDatabase.Get(d.ReadOptions.UnderlyingReadOptions, k)
+	return d.GetContext(context.Background(), k)
+}
+
+/*
+	Function GetWith gets a single value, using r in place of
+	the Database's ReadOptions. If r is nil, the Database's are used.
+*/
+func (d *Database) GetWith(r *ReadOptions, k Key) (Value, error) {
+	return d.get(d.readOptions(r), k)
+}
+
+func (d *Database) get(r *ReadOptions, k Key) (v Value, err error) {
+	v, err = d.UnderlyingDatabase.Get(r.UnderlyingReadOptions, k)
+	if err == ErrNotFound && d.nilNotFound {
+		err = nil
+	}
+	return
 }
 
 /*
-	Write an Atom to the Database.
+	Function Has reports whether a Value is stored at Key.
 */
-func (d *Database) Write(an *Atom) error {
-	return d.UnderlyingDatabase.Write(d.WriteOptions.UnderlyingWriteOptions, an.UnderlyingWriteBatch)
+func (d *Database) Has(k Key) (bool, error) {
+	return d.HasContext(context.Background(), k)
+}
+
+/*
+	Function HasWith reports whether a Value is stored at Key, using r
+	in place of the Database's ReadOptions. If r is nil, the Database's are used.
+*/
+func (d *Database) HasWith(r *ReadOptions, k Key) (bool, error) {
+	_, err := d.UnderlyingDatabase.Get(d.readOptions(r).UnderlyingReadOptions, k)
+	switch err {
+	case nil:
+		return true, nil
+	case ErrNotFound:
+		return false, nil
+	}
+	return false, err
+}
+
+/*
+	Write an Atom or InterfaceAtom to the Database.
+*/
+func (d *Database) Write(an atom) error {
+	return d.WriteContext(context.Background(), an)
+}
+
+/*
+	Function WriteWith writes an Atom or InterfaceAtom, using w in place of
+	the Database's WriteOptions. If w is nil, the Database's are used.
+*/
+func (d *Database) WriteWith(w *WriteOptions, an atom) error {
+	if d.grouped(w) {
+		//Take the operations first, so that an Atom which cannot be
+		//replayed does not leave some of them in the group.
+		var ops recording
+		if err := an.Inner().Replay(&ops); err != nil {
+			return err
+		}
+		return d.group.commit(ops.replay)
+	}
+	return d.UnderlyingDatabase.Write(d.writeOptions(w), an.Inner())
 }
 
 /*
 	Write an Atom or InterfaceAtom to the Database,
 	closing it afterward.
 */
-func (d *Database) Commit(an *Atom) error {
-	defer an.UnderlyingWriteBatch.Close()
-	return d.Write(an)
+func (d *Database) Commit(an atom) error {
+	return d.CommitContext(context.Background(), an)
+}
+
+/*
+	Function CommitWith writes an Atom or InterfaceAtom as WriteWith does,
+	closing it afterward.
+*/
+func (d *Database) CommitWith(w *WriteOptions, an atom) error {
+	defer an.Inner().Close()
+	return d.WriteWith(w, an)
 }
@@ -0,0 +1,89 @@
+package level
+
+import (
+	"bytes"
+	"context"
+)
+
+/*
+	DeleteRangeChunk is the number of deletions which DeleteRange
+	writes at once, bounding the memory it uses.
+*/
+var DeleteRangeChunk = 1024
+
+/*
+	Function PrefixLimit returns the least Key, in bytewise order, which is
+	greater than every Key beginning with prefix. If there is none,
+	as when the prefix is empty or all 0xff, it returns nil.
+
+		n, err := db.DeleteRange(prefix, level.PrefixLimit(prefix))
+*/
+func PrefixLimit(prefix Key) Key {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			limit := append(Key{}, prefix[:i+1]...)
+			limit[i]++
+			return limit
+		}
+	}
+	return nil
+}
+
+//before reports whether k comes before limit, where a nil limit is unbounded.
+func (d *Database) before(k, limit Key) bool {
+	return limit == nil || d.Options.Comparator().Compare(k, limit) < 0
+}
+
+/*
+	Function DeleteRange deletes every Key from start up to but not including
+	limit, returning how many were deleted. A nil start or limit leaves that
+	end of the range unbounded.
+
+	The deletions are written in chunks of DeleteRangeChunk, so whilst each
+	chunk is atomic, the range as a whole is not. If an error occurs, the
+	count of Keys deleted before it is returned with it. Of the backends
+	here, only memlevel deletes a range natively, and atomically, as an
+	UnderlyingRangeDeleter; golevel and levigo are written in chunks.
+*/
+func (d *Database) DeleteRange(start, limit Key) (int, error) {
+	return d.DeleteRangeContext(context.Background(), start, limit)
+}
+
+/*
+	Function DeletePrefix adds to the Atom a deletion of every Key in d which
+	begins with prefix, returning how many there are. Unlike DeleteRange,
+	the deletions are held in the Atom until it is written, so that they are
+	made atomically.
+
+	As such, the Atom's memory grows with the number, and size, of the Keys
+	beginning with prefix, without bound. For a prefix which may hold many
+	Keys, and where atomicity is not needed, use Database.DeleteRange with
+	PrefixLimit instead.
+
+	Under a Comparator other than BytewiseComparator, the Keys beginning with
+	prefix need not be adjacent, and every Key in d is examined.
+*/
+func (a *Atom) DeletePrefix(d *Database, prefix Key) (n int, err error) {
+	bytewise := d.Options.Comparator().Name() == BytewiseComparator.Name()
+
+	it := d.NewIterator()
+	defer it.Close()
+
+	if bytewise {
+		it.Seek(prefix)
+	} else {
+		it.SeekToFirst()
+	}
+	for ; it.Valid(); it.Next() {
+		if !bytes.HasPrefix(it.Key(), prefix) {
+			if bytewise {
+				break
+			}
+			continue
+		}
+		a.Delete(it.Key())
+		n++
+	}
+	err = it.Err()
+	return
+}
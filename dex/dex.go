@@ -3,6 +3,7 @@ package dex
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"github.com/TShadwell/level"
 )
 
@@ -33,19 +34,46 @@ type (
 	}
 )
 
+/*
+	Version is the version of the layout of the Keys which dex stores items at.
+	Each Key is the prefix "dex", the Version, and then the Type and Index
+	big-endian, so that the items of a Type are contiguous and ordered by Index.
+*/
+const Version = 1
+
+var keyPrefix = level.Key{'d', 'e', 'x', Version}
+
+//keyLen is the length of the Keys of items.
+const keyLen = 4 + 8 + 8
+
 func itmKey(tp Type, i Index) (level.Key, error){
 	var b bytes.Buffer
+	b.Write(keyPrefix)
 
-	if err := binary.Write(&b, binary.LittleEndian, tp); err != nil{
+	if err := binary.Write(&b, binary.BigEndian, tp); err != nil{
 		return nil, err
 	}
 
-	if err := binary.Write(&b, binary.LittleEndian, i); err != nil{
+	if err := binary.Write(&b, binary.BigEndian, i); err != nil{
 		return nil, err
 	}
 	return b.Bytes(), nil
 }
 
+//typeKey returns the prefix of the Keys of the items of tp.
+func typeKey(tp Type) level.Key {
+	k := make(level.Key, len(keyPrefix)+8)
+	copy(k, keyPrefix)
+	binary.BigEndian.PutUint64(k[len(keyPrefix):], uint64(tp))
+	return k
+}
+
+//splitKey returns the Type and Index of the item at k,
+//which must be keyLen long.
+func splitKey(k level.Key) (Type, Index) {
+	return Type(binary.BigEndian.Uint64(k[4:12])), Index(binary.BigEndian.Uint64(k[12:]))
+}
+
 func (t Type) Key(i Index) (level.Key, error){
 	return itmKey(t, i)
 }
@@ -54,6 +82,11 @@ func (i Index) Key(t Type) (level.Key, error){
 	return itmKey(t, i)
 }
 
+/*
+	ErrNoItem is returned when retrieving an item which does not exist.
+*/
+var ErrNoItem = errors.New("dex: no such item")
+
 type Dex struct {
 	*level.Database
 }
@@ -77,13 +110,60 @@ func (d Dex) RetrieveWithType(r Unmarshaler, t Type, i Index) (err error) {
 	}
 
 	var v level.Value
-	if v, err = d.Get(k); err != nil{
+	switch v, err = d.Get(k); {
+	case err == level.ErrNotFound:
+		return ErrNoItem
+	case err != nil:
 		return
+	case v == nil:
+		//A Database which returns nil for missing Keys may also
+		//return it for empty items.
+		var has bool
+		if has, err = d.Has(k); err != nil {
+			return
+		}
+		if !has {
+			return ErrNoItem
+		}
 	}
 
 	return r.UnmarshalDex(v)
 }
 
+/*
+	Function Retrieve decodes the item of r's Type at i into r.
+	If there is no such item, ErrNoItem is returned and r is unchanged.
+*/
 func (d Dex) Retrieve(r UnmarshalTyper, i Index) error {
 	return d.RetrieveWithType(r, r.TypeDex(), i)
 }
+
+/*
+	Function RemoveWithType removes the item of type t at i.
+	Removing an item which does not exist is not an error.
+*/
+func (d Dex) RemoveWithType(t Type, i Index) (err error) {
+	var k level.Key
+	if k, err = i.Key(t); err != nil {
+		return
+	}
+	return d.Delete(k)
+}
+
+/*
+	Function Remove removes the item of tp's Type at i.
+*/
+func (d Dex) Remove(tp Typer, i Index) error {
+	return d.RemoveWithType(tp.TypeDex(), i)
+}
+
+/*
+	Function Exists reports whether there is an item of type t at i.
+*/
+func (d Dex) Exists(t Type, i Index) (bool, error) {
+	k, err := i.Key(t)
+	if err != nil {
+		return false, err
+	}
+	return d.Has(k)
+}
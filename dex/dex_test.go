@@ -1,8 +1,13 @@
 package dex
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
 	"github.com/TShadwell/level"
 	gl "github.com/TShadwell/level/golevel"
+	"github.com/TShadwell/level/leveltest"
+	"github.com/TShadwell/level/memlevel"
 	"testing"
 )
 
@@ -54,3 +59,177 @@ func TestDex(t *testing.T) {
 
 
 }
+
+//open opens an empty Dex held in memory, which is destroyed after the test.
+func open(t *testing.T) Dex {
+	return Dex{leveltest.Open(t, memlevel.Level, t.Name())}
+}
+
+func TestKeyOrder(t *testing.T) {
+	var last level.Key
+	for _, i := range []Index{0, 1, 255, 256, 1 << 32} {
+		k, err := CatType.Key(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last != nil && bytes.Compare(last, k) >= 0 {
+			t.Fatalf("Key of index %d does not sort after the last", i)
+		}
+		last = k
+	}
+
+	k, err := Type(1).Key(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Compare(last, k) >= 0 {
+		t.Fatal("Keys of a type are not contiguous")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dx := open(t)
+	db := dx.Database
+
+	//Store items as versions before 1 did.
+	an := db.NewAtom()
+	for i := 0; i < 2500; i++ {
+		var k bytes.Buffer
+		binary.Write(&k, binary.LittleEndian, CatType)
+		binary.Write(&k, binary.LittleEndian, Index(i))
+		an.Put(k.Bytes(), Cat{fmt.Sprint("cat ", i)}.MarshalDex())
+	}
+	an.Put(level.Key("unrelated"), level.Value("x"))
+	//A Key of the same length as an item's, but of no Type of dex.
+	unrelated := level.Key("collectn\x00\x00\x00\x00\x00\x00\x00\x01")
+	an.Put(unrelated, level.Value("y"))
+	if err := db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+	n, err := dx.Migrate(CatType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2500 {
+		t.Fatalf("Migrated %d items, expected 2500", n)
+	}
+
+	var c Cat
+	if err = dx.Retrieve(&c, 1234); err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "cat 1234" {
+		t.Fatalf("Migrated cat 1234 is %q", c.Name)
+	}
+
+	if n, err = dx.Migrate(CatType); err != nil || n != 0 {
+		t.Fatalf("Migrating again rewrote %d items: %v", n, err)
+	}
+	if v, err := db.Get(level.Key("unrelated")); err != nil || string(v) != "x" {
+		t.Fatal("Migration changed an unrelated key: ", err)
+	}
+	if v, err := db.Get(unrelated); err != nil || string(v) != "y" {
+		t.Fatal("Migration changed an unrelated sixteen byte key: ", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	dx := open(t)
+	for i := Index(0); i < 25; i++ {
+		if err := dx.Store(Cat{fmt.Sprint("cat ", i)}, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//An item of another type.
+	if err := dx.StoreWithType(Cat{"dog"}, CatType+1, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := dx.Count(CatType); err != nil || n != 25 {
+		t.Fatalf("Counted %d cats, expected 25: %v", n, err)
+	}
+
+	var want Index
+	err := dx.Each(CatType, func(i Index, b []byte) error {
+		if i != want || string(b) != fmt.Sprint("cat ", i) {
+			t.Fatalf("Item %d is %q, expected item %d", i, b, want)
+		}
+		want++
+		return nil
+	})
+	if err != nil || want != 25 {
+		t.Fatalf("Saw %d cats: %v", want, err)
+	}
+
+	var (
+		cats  []Cat
+		next  *Index
+		pages int
+	)
+	for {
+		next, err = dx.List(CatType, next, 10, func(Index) Unmarshaler {
+			cats = append(cats, Cat{})
+			return &cats[len(cats)-1]
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		if next == nil {
+			break
+		}
+	}
+	if pages != 3 || len(cats) != 25 {
+		t.Fatalf("Listed %d cats in %d pages", len(cats), pages)
+	}
+
+	for _, limit := range []int{0, -1} {
+		if _, err = dx.List(CatType, nil, limit, nil); err != ErrLimit {
+			t.Fatalf("Expected ErrLimit from a limit of %d, got: %v", limit, err)
+		}
+	}
+	for i, c := range cats {
+		if c.Name != fmt.Sprint("cat ", i) {
+			t.Fatalf("Listed cat %d is %q", i, c.Name)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	dx := open(t)
+	db := dx.Database
+	if err := dx.Store(Cat{"Michael"}, 7); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := dx.Exists(CatType, 7); err != nil || !ok {
+		t.Fatal("Stored cat does not exist: ", err)
+	}
+
+	if err := dx.Remove(Cat{}, 7); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := dx.Exists(CatType, 7); err != nil || ok {
+		t.Fatal("Removed cat exists: ", err)
+	}
+
+	c := Cat{"unchanged"}
+	if err := dx.Retrieve(&c, 7); err != ErrNoItem {
+		t.Fatal("Expected ErrNoItem for a removed cat, got: ", err)
+	}
+	if c.Name != "unchanged" {
+		t.Fatalf("Retrieving a removed cat set it to %q", c.Name)
+	}
+
+	//As when the Database returns nil for missing Keys.
+	db.SetNilOnNotFound(true)
+	if err := dx.Retrieve(&c, 7); err != ErrNoItem {
+		t.Fatal("Expected ErrNoItem in compatibility mode, got: ", err)
+	}
+	if err := dx.Store(Cat{""}, 8); err != nil {
+		t.Fatal(err)
+	}
+	c.Name = "unchanged"
+	if err := dx.Retrieve(&c, 8); err != nil || c.Name != "" {
+		t.Fatalf("Empty cat retrieved as %q: %v", c.Name, err)
+	}
+}
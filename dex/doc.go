@@ -11,5 +11,8 @@
 		if err := dx.Retrieve(&Michael, 0); err != nil{
 			panic(err)
 		}
+
+	Items are stored in order of Type and then Index. Databases written by
+	versions before Version 1 must have their items rewritten with Migrate.
 */
 package dex
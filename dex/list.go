@@ -0,0 +1,126 @@
+package dex
+
+import (
+	"errors"
+	"github.com/TShadwell/level"
+)
+
+//each calls f with the Index of each item of tp from the Index from onward,
+//and the Iterator positioned at it, in order, until f returns false or an
+//error. The Value of the Iterator must be copied to be kept.
+func (d Dex) each(tp Type, from Index, f func(Index, *level.Iterator) (bool, error)) error {
+	start, err := tp.Key(from)
+	if err != nil {
+		return err
+	}
+	prefix := typeKey(tp)
+	limit := level.PrefixLimit(prefix)
+
+	it := d.NewIterator()
+	defer it.Close()
+
+	for it.Seek(start); it.Valid(); it.Next() {
+		k := it.Key()
+		if limit != nil && level.BytewiseComparator.Compare(k, limit) >= 0 {
+			break
+		}
+		//Keys of other lengths under the prefix are not items.
+		if len(k) != keyLen {
+			continue
+		}
+		_, i := splitKey(k)
+		var more bool
+		if more, err = f(i, it); err != nil || !more {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+/*
+	Function Each calls f with the Index and stored bytes of each item of
+	type tp, in order of Index. If f returns an error, Each stops and
+	returns it.
+*/
+func (d Dex) Each(tp Type, f func(Index, []byte) error) error {
+	return d.each(tp, 0, func(i Index, it *level.Iterator) (bool, error) {
+		return true, f(i, append([]byte{}, it.Value()...))
+	})
+}
+
+/*
+	Function Count returns the number of items of type tp.
+*/
+func (d Dex) Count(tp Type) (n int, err error) {
+	err = d.each(tp, 0, func(Index, *level.Iterator) (bool, error) {
+		n++
+		return true, nil
+	})
+	return
+}
+
+/*
+	ErrLimit is returned by List when given a limit of zero or less.
+*/
+var ErrLimit = errors.New("dex: list limit must be positive")
+
+/*
+	Function List decodes up to limit items of type tp, in order of Index,
+	beginning with the first after the cursor after, or with the first item
+	if after is nil. Each item is decoded into the Unmarshaler which into
+	returns for its Index.
+
+	The cursor of the next page is returned, or nil if there are no more
+	items. A limit of zero or less returns ErrLimit, as no page could ever
+	advance the cursor.
+
+	The cursor is an *Index, not an Index, because every Index, zero
+	included, names an item, so only nil can mean the start. As List cannot
+	know what type to decode each item into, it asks into for an
+	Unmarshaler per item, which may append to a slice as below.
+
+		var (
+			cats []Cat
+			next *dex.Index
+		)
+		for {
+			next, err = dx.List(CatType, next, 10, func(dex.Index) dex.Unmarshaler {
+				cats = append(cats, Cat{})
+				return &cats[len(cats)-1]
+			})
+			if err != nil || next == nil {
+				break
+			}
+		}
+*/
+func (d Dex) List(tp Type, after *Index, limit int, into func(Index) Unmarshaler) (next *Index, err error) {
+	if limit <= 0 {
+		return nil, ErrLimit
+	}
+
+	var from Index
+	if after != nil {
+		if *after == ^Index(0) {
+			return nil, nil
+		}
+		from = *after + 1
+	}
+
+	var (
+		n    int
+		last Index
+	)
+	err = d.each(tp, from, func(i Index, it *level.Iterator) (bool, error) {
+		//Having listed limit items, one more shows there is another page.
+		if n == limit {
+			next = &last
+			return false, nil
+		}
+		n, last = n+1, i
+		return true, into(i).UnmarshalDex(append([]byte{}, it.Value()...))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
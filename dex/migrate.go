@@ -0,0 +1,96 @@
+package dex
+
+import (
+	"encoding/binary"
+	"github.com/TShadwell/level"
+)
+
+/*
+	MigrateChunk is the number of items which Migrate rewrites in each Atom.
+*/
+var MigrateChunk = 1000
+
+//legacyKeyLen is the length of the Keys of items before Version 1,
+//which were the Type and Index little-endian, with no prefix.
+const legacyKeyLen = 8 + 8
+
+/*
+	Function Migrate rewrites the items of the given types stored by versions
+	of dex before Version 1 to the current layout, returning how many it
+	rewrote. Each item is moved atomically, in Atoms of MigrateChunk items,
+	so Migrate may be run again to finish if it fails.
+
+	Items were stored at sixteen byte Keys beginning with their Type,
+	little-endian; other sixteen byte Keys of the Database which begin
+	the same way cannot be told apart from them.
+*/
+func (d Dex) Migrate(t Type, types ...Type) (int, error) {
+	set := map[Type]bool{t: true}
+	for _, t := range types {
+		set[t] = true
+	}
+	return d.migrate(func(t Type) bool {
+		return set[t]
+	})
+}
+
+/*
+	Function MigrateEverySixteenByteKey rewrites items as Migrate does,
+	whatever their Type, taking every sixteen byte Key of the Database to
+	be an item. It must only be used on a Database which holds nothing
+	but the items of dex.
+*/
+func (d Dex) MigrateEverySixteenByteKey() (int, error) {
+	return d.migrate(func(Type) bool {
+		return true
+	})
+}
+
+func (d Dex) migrate(want func(Type) bool) (n int, err error) {
+	//Keys are rewritten whilst iterating, so those to do are taken first.
+	snap, err := d.NewSnapshot()
+	if err != nil {
+		return
+	}
+	defer snap.Close()
+
+	it := snap.NewIterator()
+	defer it.Close()
+
+	an := d.NewAtom()
+	defer an.Close()
+
+	var pending int
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		old := it.Key()
+		if len(old) != legacyKeyLen {
+			continue
+		}
+		t := Type(binary.LittleEndian.Uint64(old[:8]))
+		if !want(t) {
+			continue
+		}
+
+		var k level.Key
+		if k, err = t.Key(Index(binary.LittleEndian.Uint64(old[8:]))); err != nil {
+			return
+		}
+		an.Put(k, it.Value()).Delete(old)
+
+		if pending++; pending < MigrateChunk {
+			continue
+		}
+		if err = d.Write(an); err != nil {
+			return
+		}
+		n, pending = n+pending, 0
+		an.Clear()
+	}
+	if err = it.Err(); err != nil || pending == 0 {
+		return
+	}
+	if err = d.Write(an); err == nil {
+		n += pending
+	}
+	return
+}
@@ -5,7 +5,9 @@
 	importer may specify that heroku builds must use goleveldb for example.
 
 	The subpackage /golevel provides a *level.Level corresponding to github.com/syndtr/goleveldb,
-	and the subpackage /levigo provides one corresponding to github.com/jmhodges/levigo
+	and the subpackage /levigo provides one corresponding to github.com/jmhodges/levigo.
+	The subpackage /memlevel provides one which is held entirely in memory, for testing.
+	The subpackage /collection provides typed collections of Keys and Values within a Database.
 
 	It is important to note that there is no system that allows compatibility between the abstracted
 	types of different implimentations, trying to mix them will usually cause assertion runtime panics.
@@ -17,6 +19,7 @@
 
 		db := &level.Database{
 			Cache: lvl.NewCache(500 *level.Megabyte),
+			FilterPolicy: lvl.NewBloomFilter(10),
 			Options: lvl.NewOptions().SetCreateIfMissing(
 				true,
 			),
@@ -33,16 +36,57 @@
 			[]byte("can"),
 		)
 
+	InterfaceAtoms do the same for types which marshal themselves.
+
+		interfaceAtom := lvl.NewInterfaceAtom().PutKV(
+			can,
+		).Delete(
+			level.Key("tin"),
+		)
+
+	Iterators walk the keys of a Database in order, and must be closed
+	once finished with.
+
+		it := db.NewIterator()
+		defer it.Close()
+		for it.SeekToFirst(); it.Valid(); it.Next() {
+			fmt.Println(string(it.Key()), string(it.Value()))
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+	Snapshots provide reads which do not observe writes made after they
+	were taken.
+
+		snap, err := db.NewSnapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer snap.Close()
+		v, err := snap.Get([]byte("beans"))
+
 	As well as being Written to the UnderlyingDatabase, atoms can be committed, which
 	closes the underlying structure.
 
 		err = db.Commit(testAtom)
 
+	Implementations register themselves by name when imported, so that a Database
+	may instead be opened from a URL, and the implementation chosen by configuration.
+
+		import _ "github.com/TShadwell/level/golevel"
+
+		db, err := level.Open("golevel:///var/data/db?create=1&cache=64MB")
+
 	The /legacy package has the same interface as previous versions, which used build tags.
 
 */
 package level
 
+import (
+	"sync"
+)
+
 //Welcome to wrapper central
 
 /*
@@ -54,6 +98,17 @@ const (
 	Byte = 1 << (10 * iota)
 	Kilobyte
 	Megabyte
+	Gigabyte
+)
+
+/*
+	The compression applied to blocks of the UnderlyingDatabase.
+*/
+type Compression int
+
+const (
+	NoCompression Compression = iota
+	SnappyCompression
 )
 
 //The interfaces to which implementations must conform,
@@ -65,32 +120,85 @@ type (
 	Value             []byte
 	UnderlyingOptions interface {
 		SetCreateIfMissing(yes bool)
+		SetErrorIfExists(yes bool)
+		SetParanoidChecks(yes bool)
 		SetCache(UnderlyingCache)
-		Close()
+		SetWriteBufferSize(size int)
+		SetMaxOpenFiles(n int)
+		SetBlockSize(size int)
+		SetBlockRestartInterval(n int)
+		SetCompression(Compression)
+		SetBloomFilter(bitsPerKey int)
+		SetFilterPolicy(UnderlyingFilterPolicy)
+		SetComparator(Comparator)
+		Close() error
 	}
 	UnderlyingDatabase interface {
-		Close()
+		Close() error
 		Delete(UnderlyingWriteOptions, Key) error
 		Put(UnderlyingWriteOptions, Key, Value) error
 		Write(UnderlyingWriteOptions, UnderlyingWriteBatch) error
 		Get(UnderlyingReadOptions, Key) (Value, error)
+		NewIterator(UnderlyingReadOptions) UnderlyingIterator
+		NewSnapshot() (UnderlyingSnapshot, error)
+		CompactRange(start, limit Key) error
+		Property(name string) (string, error)
+		ApproximateSizes([]Range) ([]uint64, error)
 	}
-	UnderlyingWriteOptions interface {
+	//UnderlyingRangeDeleter may be implemented by an UnderlyingDatabase
+	//which can delete a range of Keys more efficiently than one at a time.
+	UnderlyingRangeDeleter interface {
+		DeleteRange(w UnderlyingWriteOptions, start, limit Key) (int, error)
+	}
+	//UnderlyingCompactionSuspender may be implemented by an UnderlyingDatabase
+	//which can pause its background compaction.
+	UnderlyingCompactionSuspender interface {
+		SuspendCompaction() error
+		ResumeCompaction() error
+	}
+	UnderlyingSnapshot interface {
 		Close()
+	}
+	UnderlyingIterator interface {
+		Close()
+		Valid() bool
+		Seek(Key)
+		SeekToFirst()
+		SeekToLast()
+		Next()
+		Prev()
+		Key() Key
+		Value() Value
+		Err() error
+	}
+	UnderlyingWriteOptions interface {
+		Close() error
 		SetSync(sync bool)
 	}
 	UnderlyingReadOptions interface {
-		Close()
+		Close() error
 		SetVerifyChecksums(yes bool)
+		SetFillCache(yes bool)
+		SetSnapshot(UnderlyingSnapshot)
 	}
 	UnderlyingWriteBatch interface {
 		Close()
 		Clear()
 		Delete(Key)
 		Put(Key, Value)
+		Len() int
+		Replay(AtomReplayer) error
+	}
+	//UnderlyingWriteBatchDumper may be implemented by an UnderlyingWriteBatch
+	//which stores its operations in LevelDB's write batch format.
+	UnderlyingWriteBatchDumper interface {
+		Dump() []byte
 	}
 	UnderlyingCache interface {
-		Close()
+		Close() error
+	}
+	UnderlyingFilterPolicy interface {
+		Close() error
 	}
 )
 
@@ -99,11 +207,16 @@ type (
 	//Database UnderlyingOptions
 	Options struct {
 		UnderlyingOptions
+		comparator Comparator
 	}
 	//LRU Cache
 	Cache struct {
 		UnderlyingCache
 	}
+	//A policy for filtering which blocks may hold a Key
+	FilterPolicy struct {
+		UnderlyingFilterPolicy
+	}
 	//General write UnderlyingOptions
 	WriteOptions struct {
 		UnderlyingWriteOptions
@@ -115,10 +228,28 @@ type (
 	//A levelDB UnderlyingDatabase
 	Database struct {
 		UnderlyingDatabase
-		Cache *Cache
+		Cache        *Cache
+		FilterPolicy *FilterPolicy
 		*Options
 		*ReadOptions
 		*WriteOptions
+		level       *Level
+		nilNotFound bool
+		group       *committer
+		inflight    sync.WaitGroup
+		workers     chan struct{}
+		workersOnce sync.Once
+	}
+	//type Snapshot is a consistent, read-only view of a Database
+	//at the point it was taken.
+	Snapshot struct {
+		UnderlyingSnapshot
+		*ReadOptions
+		db *Database
+	}
+	//type Iterator walks the keys of a Database in order.
+	Iterator struct {
+		UnderlyingIterator
 	}
 	//type Atom represents series of deletions and writes that all fail and
 	//do not commit if one fails.
@@ -144,11 +275,40 @@ type (
 		ValueMarshaler
 	}
 
+	ValueUnmarshaler interface {
+		UnmarshalValue(Value) error
+	}
+
+	//type AtomReplayer is given the operations of an Atom, in order,
+	//by Atom.Replay.
+	AtomReplayer interface {
+		Put(Key, Value)
+		Delete(Key)
+	}
+
+	//type Comparator orders the Keys of a Database. Its Name is stored
+	//with the Database, and must not change whilst the ordering is in use.
+	Comparator interface {
+		Compare(a, b Key) int
+		Name() string
+	}
+
+	//type ComparatorShortener is a Comparator which can find short Keys
+	//that fall between others, which LevelDB uses to shrink its indexes.
+	ComparatorShortener interface {
+		Comparator
+		//Separator returns a Key which is at least start and less than limit.
+		Separator(start, limit Key) Key
+		//Successor returns a Key which is at least k.
+		Successor(k Key) Key
+	}
+
 	atom interface {
 		Inner() UnderlyingWriteBatch
 	}
 	UnderlyingLevel interface {
 		NewLRUCache(capacity int) UnderlyingCache
+		NewBloomFilter(bitsPerKey int) UnderlyingFilterPolicy
 		DestroyDatabase(name string, o UnderlyingOptions) error
 		RepairDatabase(name string, o UnderlyingOptions) error
 		OpenDatabase(name string, o UnderlyingOptions) (UnderlyingDatabase, error)
@@ -167,26 +327,37 @@ func (k Key) MarshalKey() Key {
 	return k
 }
 
-func (c *Cache) Close() {
+func (c *Cache) Close() error {
 	if c != nil && c.UnderlyingCache != nil {
-		c.UnderlyingCache.Close()
+		return c.UnderlyingCache.Close()
+	}
+	return nil
+}
+
+func (f *FilterPolicy) Close() error {
+	if f != nil && f.UnderlyingFilterPolicy != nil {
+		return f.UnderlyingFilterPolicy.Close()
 	}
+	return nil
 }
 
-func (o *Options) Close() {
+func (o *Options) Close() error {
 	if o != nil && o.UnderlyingOptions != nil {
-		o.UnderlyingOptions.Close()
+		return o.UnderlyingOptions.Close()
 	}
+	return nil
 }
 
-func (r *ReadOptions) Close() {
+func (r *ReadOptions) Close() error {
 	if r != nil && r.UnderlyingReadOptions != nil {
-		r.UnderlyingReadOptions.Close()
+		return r.UnderlyingReadOptions.Close()
 	}
+	return nil
 }
 
-func (w *WriteOptions) Close() {
+func (w *WriteOptions) Close() error {
 	if w != nil && w.UnderlyingWriteOptions != nil {
-		w.UnderlyingWriteOptions.Close()
+		return w.UnderlyingWriteOptions.Close()
 	}
+	return nil
 }
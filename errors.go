@@ -0,0 +1,90 @@
+package level
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+	ErrNotFound is returned when a Key is not present in the Database.
+	All implementations return it, so that a missing Key may be told
+	apart from one which stores an empty Value.
+*/
+var ErrNotFound = errors.New("level: key not found")
+
+/*
+	ErrUnknownProperty is returned when asking a Database
+	for a property which it does not have.
+*/
+var ErrUnknownProperty = errors.New("level: unknown property")
+
+/*
+	ErrComparatorUnsupported is returned when opening a Database with
+	a Comparator which the implementation cannot use.
+*/
+var ErrComparatorUnsupported = errors.New("level: implementation supports only BytewiseComparator")
+
+/*
+	ErrCompactionUnsupported is returned when suspending the compaction
+	of a Database whose implementation cannot do so.
+*/
+var ErrCompactionUnsupported = errors.New("level: implementation cannot suspend compaction")
+
+/*
+	ErrCorruptAtom is returned when unmarshaling an Atom from data
+	which is not in LevelDB's write batch format.
+*/
+var ErrCorruptAtom = errors.New("level: corrupt atom")
+
+/*
+	A ComparatorMismatchError is returned when a Database is opened with
+	a Comparator other than the one it was created with.
+	Existing is empty if the implementation does not report it.
+*/
+type ComparatorMismatchError struct {
+	Existing, Requested string
+	//The error reported by the implementation, if any.
+	Err error
+}
+
+func (c *ComparatorMismatchError) Error() string {
+	if c.Existing == "" {
+		return fmt.Sprintf("level: database was created with a Comparator other than %q", c.Requested)
+	}
+	return fmt.Sprintf("level: database was created with Comparator %q, not %q", c.Existing, c.Requested)
+}
+
+/*
+	Errors aggregates several errors, such as those encountered
+	whilst closing the parts of a Database.
+*/
+type Errors []error
+
+func (e Errors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+/*
+	Function Append adds err to the Errors, if it is not nil.
+*/
+func (e Errors) Append(err error) Errors {
+	if err == nil {
+		return e
+	}
+	return append(e, err)
+}
+
+/*
+	Function Err returns the Errors as an error, or nil if there are none.
+*/
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
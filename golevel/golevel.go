@@ -1,18 +1,26 @@
 package golevel
 
 import (
+	stderrors "errors"
 	"github.com/TShadwell/level"
 	"github.com/syndtr/goleveldb/leveldb"
 	C "github.com/syndtr/goleveldb/leveldb/cache"
+	"github.com/syndtr/goleveldb/leveldb/comparer"
 	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/storage"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
 var Level *level.Level
 
 func init() {
 	Level = level.New(ulevel{})
+	level.Register("golevel", Level)
 }
 
 type ulevel struct{}
@@ -22,16 +30,11 @@ type db struct {
 	*storage.FileStorage
 }
 
-func (d db) Close() {
-	e := d.DB.Close()
-	//Fix this nasty kludge
-	if e != nil {
-		panic(e)
-	}
-	e = d.FileStorage.Close()
-	if e != nil {
-		panic(e)
-	}
+func (d db) Close() error {
+	var errs level.Errors
+	errs = errs.Append(d.DB.Close())
+	errs = errs.Append(d.FileStorage.Close())
+	return errs.Err()
 }
 
 func (d db) Put(w level.UnderlyingWriteOptions, k level.Key, v level.Value) error {
@@ -43,10 +46,10 @@ func (d db) Delete(w level.UnderlyingWriteOptions, k level.Key) error {
 }
 
 func (d db) Get(ro level.UnderlyingReadOptions, k level.Key) (v level.Value, e error) {
-	v, e = d.DB.Get(k, ro.(ropts).ReadOptions)
+	r := ro.(*ropts)
+	v, e = d.reader(r).Get(k, r.ReadOptions)
 	if e == errors.ErrNotFound {
-		//so it works like levigo
-		e = nil
+		e = level.ErrNotFound
 	}
 	return
 }
@@ -55,6 +58,102 @@ func (d db) Write(w level.UnderlyingWriteOptions, a level.UnderlyingWriteBatch)
 	return d.DB.Write(a.(wb).Batch, w.(wopts).WriteOptions)
 }
 
+func (d db) NewIterator(ro level.UnderlyingReadOptions) level.UnderlyingIterator {
+	r := ro.(*ropts)
+	return iter{d.reader(r).NewIterator(r.ReadOptions)}
+}
+
+//reader is satisfied by both *leveldb.DB and *leveldb.Snapshot.
+type reader interface {
+	Get([]byte, *opt.ReadOptions) ([]byte, error)
+	NewIterator(*opt.ReadOptions) iterator.Iterator
+}
+
+func (d db) reader(r *ropts) reader {
+	if r.snapshot != nil {
+		return r.snapshot
+	}
+	return d.DB
+}
+
+func (d db) NewSnapshot() (level.UnderlyingSnapshot, error) {
+	s, err := d.DB.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return snap{s}, nil
+}
+
+func (d db) CompactRange(start, limit level.Key) error {
+	return d.DB.CompactRange(leveldb.Range{Start: start, Limit: limit})
+}
+
+func (d db) Property(name string) (string, error) {
+	v, err := d.DB.GetProperty(name)
+	if err == errors.ErrNotFound {
+		//goleveldb does not know the property.
+		return "", level.ErrUnknownProperty
+	}
+	return v, err
+}
+
+func (d db) ApproximateSizes(r []level.Range) ([]uint64, error) {
+	rr := make([]leveldb.Range, len(r))
+	for i, v := range r {
+		rr[i] = leveldb.Range{Start: v.Start, Limit: v.Limit}
+	}
+	s, err := d.DB.GetApproximateSizes(rr)
+	return []uint64(s), err
+}
+
+type snap struct {
+	*leveldb.Snapshot
+}
+
+func (s snap) Close() {
+	s.Snapshot.Release()
+}
+
+type iter struct {
+	iterator.Iterator
+}
+
+func (i iter) Seek(k level.Key) {
+	i.Iterator.Seek(k)
+}
+
+func (i iter) SeekToFirst() {
+	i.Iterator.First()
+}
+
+func (i iter) SeekToLast() {
+	i.Iterator.Last()
+}
+
+func (i iter) Next() {
+	i.Iterator.Next()
+}
+
+func (i iter) Prev() {
+	i.Iterator.Prev()
+}
+
+func (i iter) Key() level.Key {
+	return i.Iterator.Key()
+}
+
+func (i iter) Value() level.Value {
+	return i.Iterator.Value()
+}
+
+func (i iter) Err() error {
+	return i.Iterator.Error()
+}
+
+func (i iter) Close() {
+	i.Iterator.Release()
+}
+
 type wb struct {
 	*leveldb.Batch
 }
@@ -82,22 +181,50 @@ func (w wb) Close() {
 	w.Batch = nil
 }
 
+func (w wb) Len() int {
+	return w.batch().Len()
+}
+
+func (w wb) Replay(r level.AtomReplayer) error {
+	return w.batch().Replay(replay{r})
+}
+
+func (w wb) Dump() []byte {
+	return w.batch().Dump()
+}
+
+//replay adapts a level.AtomReplayer to a leveldb.BatchReplay.
+type replay struct {
+	level.AtomReplayer
+}
+
+func (r replay) Put(k, v []byte) {
+	r.AtomReplayer.Put(k, v)
+}
+
+func (r replay) Delete(k []byte) {
+	r.AtomReplayer.Delete(k)
+}
+
 type ropts struct {
 	*opt.ReadOptions
+	snapshot *leveldb.Snapshot
 }
 
-func (r ropts) Close() {
+func (r *ropts) Close() error {
 	r.ReadOptions = nil
+	r.snapshot = nil
+	return nil
 }
 
-func (r ropts) readOptions() *opt.ReadOptions {
+func (r *ropts) readOptions() *opt.ReadOptions {
 	if r.ReadOptions == nil {
 		r.ReadOptions = new(opt.ReadOptions)
 	}
 	return r.ReadOptions
 }
 
-func (r ropts) SetVerifyChecksums(b bool) {
+func (r *ropts) SetVerifyChecksums(b bool) {
 	if b {
 		r.readOptions().Flag |= opt.RFVerifyChecksums
 	} else {
@@ -105,6 +232,22 @@ func (r ropts) SetVerifyChecksums(b bool) {
 	}
 }
 
+func (r *ropts) SetFillCache(b bool) {
+	if b {
+		r.readOptions().Flag &^= opt.RFDontFillCache
+	} else {
+		r.readOptions().Flag |= opt.RFDontFillCache
+	}
+}
+
+func (r *ropts) SetSnapshot(s level.UnderlyingSnapshot) {
+	if s == nil {
+		r.snapshot = nil
+		return
+	}
+	r.snapshot = s.(snap).Snapshot
+}
+
 type opts struct {
 	*opt.Options
 }
@@ -113,8 +256,9 @@ type wopts struct {
 	*opt.WriteOptions
 }
 
-func (w wopts) Close() {
+func (w wopts) Close() error {
 	w.WriteOptions = nil
+	return nil
 }
 
 func (w wopts) writeOptions() *opt.WriteOptions {
@@ -132,8 +276,9 @@ func (w wopts) SetSync(b bool) {
 	}
 }
 
-func (o opts) Close() {
+func (o opts) Close() error {
 	o.Options = nil
+	return nil
 }
 
 func (o opts) options() *opt.Options {
@@ -152,18 +297,108 @@ func (o opts) SetCreateIfMissing(b bool) {
 	}
 }
 
+func (o opts) SetErrorIfExists(b bool) {
+	if b {
+		o.options().Flag |= opt.OFErrorIfExist
+	} else {
+		o.options().Flag &^= opt.OFErrorIfExist
+	}
+}
+
+func (o opts) SetParanoidChecks(b bool) {
+	if b {
+		o.options().Flag |= opt.OFParanoidCheck
+	} else {
+		o.options().Flag &^= opt.OFParanoidCheck
+	}
+}
+
 func (o opts) SetCache(c level.UnderlyingCache) {
 	o.Options.BlockCache = c.(che).Cache
 }
 
+func (o opts) SetWriteBufferSize(size int) {
+	o.options().WriteBuffer = size
+}
+
+func (o opts) SetMaxOpenFiles(n int) {
+	o.options().MaxOpenFiles = n
+}
+
+func (o opts) SetBlockSize(size int) {
+	o.options().BlockSize = size
+}
+
+func (o opts) SetBlockRestartInterval(n int) {
+	o.options().BlockRestartInterval = n
+}
+
+func (o opts) SetCompression(c level.Compression) {
+	switch c {
+	case level.NoCompression:
+		o.options().CompressionType = opt.NoCompression
+	case level.SnappyCompression:
+		o.options().CompressionType = opt.SnappyCompression
+	}
+}
+
+func (o opts) SetBloomFilter(bitsPerKey int) {
+	o.options().Filter = filter.NewBloomFilter(bitsPerKey)
+}
+
+func (o opts) SetComparator(c level.Comparator) {
+	if c == level.BytewiseComparator {
+		o.options().Comparer = comparer.BytesComparer
+		return
+	}
+	o.options().Comparer = cmp{c}
+}
+
+//cmp adapts a level.Comparator to a goleveldb comparer.Comparer.
+type cmp struct {
+	level.Comparator
+}
+
+func (c cmp) Compare(a, b []byte) int {
+	return c.Comparator.Compare(a, b)
+}
+
+func (c cmp) Separator(a, b []byte) []byte {
+	if s, ok := c.Comparator.(level.ComparatorShortener); ok {
+		return s.Separator(a, b)
+	}
+	return a
+}
+
+func (c cmp) Successor(b []byte) []byte {
+	if s, ok := c.Comparator.(level.ComparatorShortener); ok {
+		return s.Successor(b)
+	}
+	return b
+}
+
+func (o opts) SetFilterPolicy(f level.UnderlyingFilterPolicy) {
+	o.options().Filter = f.(filt).Filter
+}
+
+type filt struct {
+	filter.Filter
+}
+
+//goleveldb filters hold no resources.
+func (filt) Close() error {
+	return nil
+}
+
 type che struct {
 	C.Cache
 }
 
-func (c che) Close() {
+func (c che) Close() error {
 	c.Cache.Purge(func() {
 		c.Cache = nil
 	})
+	return nil
 }
 
 func (ulevel) OpenDatabase(name string, o level.UnderlyingOptions) (dtb level.UnderlyingDatabase, err error) {
@@ -172,34 +407,162 @@ func (ulevel) OpenDatabase(name string, o level.UnderlyingOptions) (dtb level.Un
 		return
 	}
 	var dtbe *leveldb.DB
-	dtbe, err = leveldb.Open(stor, o.(opts).Options)
+	if dtbe, err = leveldb.Open(stor, o.(opts).Options); err != nil {
+		stor.Close()
+		err = comparerError(err)
+		return
+	}
 	dtb = db{dtbe, stor}
 	return
 }
 
+//comparerError translates goleveldb's report of a mismatched comparer, an
+//ErrManifestCorrupted of the field "comparer", whose reason is
+//"mismatch: want '<requested>', got '<existing>'".
+func comparerError(e error) error {
+	var m *leveldb.ErrManifestCorrupted
+	if !stderrors.As(e, &m) {
+		//ErrCorrupted may not unwrap to the error it holds.
+		var c *errors.ErrCorrupted
+		if !stderrors.As(e, &c) || !stderrors.As(c.Err, &m) {
+			return e
+		}
+	}
+	if m.Field != "comparer" {
+		return e
+	}
+
+	const (
+		want = "mismatch: want '"
+		got  = "', got '"
+	)
+	r := m.Reason
+	if !strings.HasPrefix(r, want) || !strings.HasSuffix(r, "'") {
+		return e
+	}
+	names := r[len(want) : len(r)-1]
+	k := strings.LastIndex(names, got)
+	if k < 0 {
+		return e
+	}
+	return &level.ComparatorMismatchError{
+		Existing:  names[k+len(got):],
+		Requested: names[:k],
+		Err:       e,
+	}
+}
+
 func (ulevel) NewLRUCache(capacity int) level.UnderlyingCache {
 	return che{
 		Cache: C.NewLRUCache(capacity),
 	}
 }
 
-//BUG: Destroy database not written
-func (ulevel) DestroyDatabase(name string, o level.UnderlyingOptions) error {
-	return nil
+func (ulevel) NewBloomFilter(bitsPerKey int) level.UnderlyingFilterPolicy {
+	return filt{filter.NewBloomFilter(bitsPerKey)}
 }
 
-//BUG: Repair database not in go-leveldb
-func (ulevel) RepairDatabase(name string, o level.UnderlyingOptions) error {
+//isDatabaseFile reports whether a file name is one which LevelDB creates.
+func isDatabaseFile(name string) bool {
+	switch name {
+	case "CURRENT", "LOCK", "LOG", "LOG.old":
+		return true
+	}
+	if strings.HasPrefix(name, "MANIFEST-") {
+		return true
+	}
+	switch filepath.Ext(name) {
+	case ".log", ".sst", ".ldb", ".tmp", ".dbtmp":
+		return true
+	}
+	return false
+}
+
+/*
+	DestroyDatabase removes the database stored at name.
+	Opening the storage takes the database's lock, so this fails
+	rather than destroying a database which is in use. Only files
+	which LevelDB would have created are removed, and the directory
+	is only removed if it is then empty.
+*/
+func (ulevel) DestroyDatabase(name string, o level.UnderlyingOptions) (err error) {
+	if _, err = os.Stat(name); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return
+	}
+
+	stor, err := storage.OpenFile(name)
+	if err != nil {
+		return
+	}
+
+	dir, err := os.Open(name)
+	if err != nil {
+		stor.Close()
+		return
+	}
+	names, err := dir.Readdirnames(-1)
+	dir.Close()
+	if err != nil {
+		stor.Close()
+		return
+	}
+
+	for _, n := range names {
+		//The lock goes last, once the storage has released it.
+		if n == "LOCK" || !isDatabaseFile(n) {
+			continue
+		}
+		if e := os.Remove(filepath.Join(name, n)); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	if e := stor.Close(); e != nil && err == nil {
+		err = e
+	}
+	if err != nil {
+		return
+	}
+
+	if err = os.Remove(filepath.Join(name, "LOCK")); err != nil && !os.IsNotExist(err) {
+		return
+	}
+
+	//Ignore the error, the directory may hold files which are not ours.
+	os.Remove(name)
 	return nil
 }
 
+/*
+	RepairDatabase rebuilds the manifest of the database stored at name
+	from its table files, using goleveldb's recovery.
+*/
+func (ulevel) RepairDatabase(name string, o level.UnderlyingOptions) (err error) {
+	stor, err := storage.OpenFile(name)
+	if err != nil {
+		return
+	}
+
+	dtbe, err := leveldb.Recover(stor, o.(opts).Options)
+	if err == nil {
+		err = dtbe.Close()
+	}
+
+	if e := stor.Close(); e != nil && err == nil {
+		err = e
+	}
+	return
+}
+
 func (ulevel) NewOptions() level.UnderlyingOptions {
 	return opts{
 		new(opt.Options),
 	}
 }
 func (ulevel) NewReadOptions() level.UnderlyingReadOptions {
-	return ropts{
+	return &ropts{
 		ReadOptions: new(opt.ReadOptions),
 	}
 }
@@ -0,0 +1,39 @@
+package golevel
+
+import (
+	"fmt"
+	"github.com/TShadwell/level"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/errors"
+	"testing"
+)
+
+func TestComparerError(t *testing.T) {
+	mismatch := &leveldb.ErrManifestCorrupted{
+		Field:  "comparer",
+		Reason: "mismatch: want 'leveltest.Reverse', got 'leveldb.BytewiseComparator'",
+	}
+	for _, err := range []error{
+		mismatch,
+		&errors.ErrCorrupted{Err: mismatch},
+		fmt.Errorf("opening: %w", &errors.ErrCorrupted{Err: mismatch}),
+	} {
+		m, ok := comparerError(err).(*level.ComparatorMismatchError)
+		if !ok {
+			t.Fatalf("%v was not translated", err)
+		}
+		if m.Requested != "leveltest.Reverse" || m.Existing != "leveldb.BytewiseComparator" || m.Err != err {
+			t.Fatalf("%v was translated as %+v", err, m)
+		}
+	}
+
+	for _, err := range []error{
+		&leveldb.ErrManifestCorrupted{Field: "log-number", Reason: "missing"},
+		&errors.ErrCorrupted{Err: fmt.Errorf("checksum mismatch")},
+		errors.ErrNotFound,
+	} {
+		if got := comparerError(err); got != err {
+			t.Fatalf("%v was translated as %v", err, got)
+		}
+	}
+}
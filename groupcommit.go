@@ -0,0 +1,100 @@
+package level
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+	Function SetGroupCommit enables group commit: Puts, Deletes and Writes
+	made within window of one another are merged into a single Atom, which
+	is written once, and its result returned to each caller. With a synced
+	WriteOptions, this shares one sync between many concurrent writes.
+
+	Each write still returns only once it has been written, so a caller
+	waits up to window longer; if the merged Atom fails, every write in it
+	fails. The operations of a single Atom are never split between groups.
+	Writes given WriteOptions other than the Database's, as by PutWith,
+	are not grouped; PutWith and the like given nil are.
+
+	A window of zero or less disables group commit. SetGroupCommit must not
+	be called concurrently with writes.
+*/
+func (d *Database) SetGroupCommit(window time.Duration) *Database {
+	if window <= 0 {
+		d.group = nil
+		return d
+	}
+	d.group = &committer{
+		d:      d,
+		window: window,
+	}
+	return d
+}
+
+//committer merges concurrent writes. The first write of a group leads it:
+//it waits out the window, and then writes the group for every member.
+type committer struct {
+	d      *Database
+	window time.Duration
+
+	sync.Mutex
+	pending *group
+
+	//flushing is held whilst a group is written, so that the next group
+	//keeps gathering writes until the last is done.
+	flushing sync.Mutex
+}
+
+type group struct {
+	*Atom
+	done chan struct{}
+	err  error
+}
+
+//commit adds operations to the pending group with add,
+//and returns once the group has been written.
+func (c *committer) commit(add func(AtomReplayer)) error {
+	c.Lock()
+	g, leader := c.pending, false
+	if g == nil {
+		g = &group{
+			Atom: c.d.level.NewAtom(),
+			done: make(chan struct{}),
+		}
+		c.pending, leader = g, true
+	}
+	add(g.UnderlyingWriteBatch)
+	c.Unlock()
+
+	if !leader {
+		<-g.done
+		return g.err
+	}
+
+	time.Sleep(c.window)
+	c.flushing.Lock()
+	defer c.flushing.Unlock()
+
+	c.Lock()
+	c.pending = nil
+	c.Unlock()
+
+	g.err = c.d.UnderlyingDatabase.Write(c.d.WriteOptions.UnderlyingWriteOptions, g.UnderlyingWriteBatch)
+	g.Close()
+	close(g.done)
+	return g.err
+}
+
+//drain waits for the group gathering writes, if any, and the group
+//being written to be written.
+func (c *committer) drain() {
+	c.Lock()
+	g := c.pending
+	c.Unlock()
+	if g != nil {
+		<-g.done
+	}
+	c.flushing.Lock()
+	c.flushing.Unlock()
+}
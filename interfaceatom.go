@@ -0,0 +1,72 @@
+package level
+
+func (l *Level) NewInterfaceAtom() *InterfaceAtom {
+	return &InterfaceAtom{
+		l.NewAtom(),
+	}
+}
+
+/*
+	Delete the Value stored at the marshaled Key.
+*/
+func (i *InterfaceAtom) Delete(k KeyMarshaler) *InterfaceAtom {
+	i.Atom.Delete(k.MarshalKey())
+	return i
+}
+
+/*
+	Store the marshaled Value at the marshaled Key.
+*/
+func (i *InterfaceAtom) Put(k KeyMarshaler, v ValueMarshaler) *InterfaceAtom {
+	i.Atom.Put(k.MarshalKey(), v.MarshalValue())
+	return i
+}
+
+/*
+	Store a KeyValueMarshaler, which provides its own Key.
+*/
+func (i *InterfaceAtom) PutKV(kv KeyValueMarshaler) *InterfaceAtom {
+	return i.Put(kv, kv)
+}
+
+/*
+	Empty the writes and deletes of this InterfaceAtom.
+*/
+func (i *InterfaceAtom) Clear() *InterfaceAtom {
+	i.Atom.Clear()
+	return i
+}
+
+func (i *InterfaceAtom) Close() *InterfaceAtom {
+	i.Atom.Close()
+	return i
+}
+
+/*
+	Puts a single KeyValueMarshaler into the Database.
+	For batch puts, use an InterfaceAtom.
+*/
+func (d *Database) PutKV(kv KeyValueMarshaler) error {
+	return d.Put(kv.MarshalKey(), kv.MarshalValue())
+}
+
+/*
+	Gets the Value stored at the marshaled Key, and unmarshals it into v.
+	If the Key is not present, ErrNotFound is returned and v is untouched,
+	even if SetNilOnNotFound is enabled.
+*/
+func (d *Database) GetInto(k KeyMarshaler, v ValueUnmarshaler) error {
+	val, err := d.UnderlyingDatabase.Get(d.ReadOptions.UnderlyingReadOptions, k.MarshalKey())
+	if err != nil {
+		return err
+	}
+	return v.UnmarshalValue(val)
+}
+
+/*
+	Copies a Value into v.
+*/
+func (v *Value) UnmarshalValue(val Value) error {
+	*v = append((*v)[:0], val...)
+	return nil
+}
@@ -0,0 +1,70 @@
+package level
+
+import (
+	"context"
+)
+
+/*
+	Function NewIterator returns an Iterator over the Database, using
+	the Database's ReadOptions. The Iterator is initially invalid;
+	it must be positioned with one of the Seek functions before use.
+*/
+func (d *Database) NewIterator() *Iterator {
+	return d.NewIteratorContext(context.Background())
+}
+
+/*
+	Function NewIteratorWith returns an Iterator over the Database, using r
+	in place of the Database's ReadOptions. If r is nil, the Database's are used.
+*/
+func (d *Database) NewIteratorWith(r *ReadOptions) *Iterator {
+	return &Iterator{
+		d.UnderlyingDatabase.NewIterator(d.readOptions(r).UnderlyingReadOptions),
+	}
+}
+
+/*
+	Function Seek moves the Iterator to the first Key at or past k.
+*/
+func (i *Iterator) Seek(k Key) *Iterator {
+	i.UnderlyingIterator.Seek(k)
+	return i
+}
+
+/*
+	Function SeekToFirst moves the Iterator to the first Key in the Database.
+*/
+func (i *Iterator) SeekToFirst() *Iterator {
+	i.UnderlyingIterator.SeekToFirst()
+	return i
+}
+
+/*
+	Function SeekToLast moves the Iterator to the last Key in the Database.
+*/
+func (i *Iterator) SeekToLast() *Iterator {
+	i.UnderlyingIterator.SeekToLast()
+	return i
+}
+
+/*
+	Function Next moves the Iterator to the next Key.
+*/
+func (i *Iterator) Next() *Iterator {
+	i.UnderlyingIterator.Next()
+	return i
+}
+
+/*
+	Function Prev moves the Iterator to the previous Key.
+*/
+func (i *Iterator) Prev() *Iterator {
+	i.UnderlyingIterator.Prev()
+	return i
+}
+
+func (i *Iterator) Close() {
+	if i != nil && i.UnderlyingIterator != nil {
+		i.UnderlyingIterator.Close()
+	}
+}
@@ -7,5 +7,5 @@ import (
 )
 
 func init() {
-	lvl = golevel.Level()
+	lvl = golevel.Level
 }
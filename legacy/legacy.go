@@ -1,16 +1,45 @@
 /*
 	Package legacy provides a replacement package for packages that used buildtag dependant versions of level.
 
-	UNFINISHED
+	By default it is backed by levigo; building with the purego tag backs it with goleveldb instead.
+	Types are made ready for use when they are first used, so that zero values may be used directly.
+
+		db := new(legacy.Database).SetOptions(
+			new(legacy.Options).SetCreateIfMissing(
+				true,
+			).SetCacheSize(
+				500 * legacy.Megabyte,
+			),
+		)
+
+		if err := db.Open(path + "/leveldb/"); err != nil {
+			t.Fatal("Error whilst loading DB:", err)
+		}
+
+		err = db.Commit(
+			new(legacy.Atom).Put(
+				[]byte("beans"),
+				[]byte("can"),
+			),
+		)
+
+	As in previous versions, Getting a missing Key returns a nil Value and a nil error.
 */
 package legacy
 
 import (
 	"github.com/TShadwell/level"
+	"sync"
 )
 
 var lvl *level.Level
 
+const (
+	Byte     = level.Byte
+	Kilobyte = level.Kilobyte
+	Megabyte = level.Megabyte
+)
+
 type (
 	BytesSize     level.BytesSize
 	Key           level.Key
@@ -41,6 +70,9 @@ type (
 	options      level.UnderlyingOptions
 	cache        level.UnderlyingCache
 	writeOptions level.UnderlyingWriteOptions
+	readOptions  level.UnderlyingReadOptions
+	writeBatch   level.UnderlyingWriteBatch
+	database     level.UnderlyingDatabase
 )
 
 func (v Value) MarshalValue() Value {
@@ -53,16 +85,16 @@ func (k Key) MarshalKey() Key {
 //Options functions
 
 func (o *Options) Inner() options {
-	if o == nil {
-		o = (*Options)(lvl.NewOptions())
-	}
-	return o.UnderlyingOptions
+	return o.down().UnderlyingOptions
 }
 
 func (o *Options) down() *level.Options {
 	if o == nil {
 		o = (*Options)(lvl.NewOptions())
 	}
+	if o.UnderlyingOptions == nil {
+		o.UnderlyingOptions = lvl.NewOptions().UnderlyingOptions
+	}
 	return (*level.Options)(o)
 }
 
@@ -71,13 +103,44 @@ func (o *Options) SetCreateIfMissing(b bool) *Options {
 	return o
 }
 
+//sized holds the Caches made by SetCacheSize, by the Options they were set
+//on, until those Options are given to a Database, which adopts and closes it.
+var sized = struct {
+	sync.Mutex
+	caches map[*Options]*Cache
+}{caches: make(map[*Options]*Cache)}
+
+//sizedCache removes and returns the Cache made for o by SetCacheSize, if any.
+func sizedCache(o *Options) *Cache {
+	sized.Lock()
+	defer sized.Unlock()
+	c := sized.caches[o]
+	delete(sized.caches, o)
+	return c
+}
+
 func (o *Options) SetCache(c *Cache) *Options {
-	o.down().SetCache((*level.Cache)(c))
+	o.down().SetCache(c.down())
+	//A cache made by SetCacheSize is no longer used.
+	if own := sizedCache(o); own != nil && own != c {
+		own.down().Close()
+	}
 	return o
 }
 
+/*
+	Function SetCacheSize sets a new Cache of size on the Options,
+	which is closed with the Database they are given to.
+*/
 func (o *Options) SetCacheSize(size BytesSize) *Options {
-	o.SetCache(new(Cache).Size(size))
+	if o == nil {
+		o = new(Options)
+	}
+	c := new(Cache).Size(size)
+	o.SetCache(c)
+	sized.Lock()
+	sized.caches[o] = c
+	sized.Unlock()
 	return o
 }
 
@@ -92,7 +155,11 @@ func (c *Cache) Inner() cache {
 }
 
 func (c *Cache) Size(b BytesSize) *Cache {
-	c = (*Cache)(lvl.NewCache((level.BytesSize)(b)))
+	if c == nil {
+		c = new(Cache)
+	}
+	c.down().Close()
+	*c = (Cache)(*lvl.NewCache((level.BytesSize)(b)))
 	return c
 }
 
@@ -101,6 +168,9 @@ func (w *WriteOptions) down() *level.WriteOptions {
 	if w == nil {
 		w = (*WriteOptions)(lvl.NewWriteOptions())
 	}
+	if w.UnderlyingWriteOptions == nil {
+		w.UnderlyingWriteOptions = lvl.NewWriteOptions().UnderlyingWriteOptions
+	}
 	return (*level.WriteOptions)(w)
 }
 
@@ -112,3 +182,211 @@ func (w *WriteOptions) SetSync(b bool) *WriteOptions {
 	w.down().SetSync(b)
 	return w
 }
+
+//ReadOptions functions
+func (r *ReadOptions) down() *level.ReadOptions {
+	if r == nil {
+		r = (*ReadOptions)(lvl.NewReadOptions())
+	}
+	if r.UnderlyingReadOptions == nil {
+		r.UnderlyingReadOptions = lvl.NewReadOptions().UnderlyingReadOptions
+	}
+	return (*level.ReadOptions)(r)
+}
+
+func (r *ReadOptions) Inner() readOptions {
+	return r.down().UnderlyingReadOptions
+}
+
+func (r *ReadOptions) SetVerifyChecksums(b bool) *ReadOptions {
+	r.down().SetVerifyChecksums(b)
+	return r
+}
+
+//Atom functions
+func (a *Atom) down() *level.Atom {
+	if a == nil {
+		a = (*Atom)(lvl.NewAtom())
+	}
+	if a.UnderlyingWriteBatch == nil {
+		a.UnderlyingWriteBatch = lvl.NewAtom().UnderlyingWriteBatch
+	}
+	return (*level.Atom)(a)
+}
+
+func (a *Atom) Inner() writeBatch {
+	return a.down().UnderlyingWriteBatch
+}
+
+/*
+	Empty the writes and deletes of this Atom.
+*/
+func (a *Atom) Clear() *Atom {
+	a.down().Clear()
+	return a
+}
+
+func (a *Atom) Close() *Atom {
+	a.down().Close()
+	return a
+}
+
+/*
+	Delete a Value from the Database.
+*/
+func (a *Atom) Delete(k Key) *Atom {
+	a.down().Delete(level.Key(k))
+	return a
+}
+
+/*
+	Store a Value at Key.
+*/
+func (a *Atom) Put(k Key, v Value) *Atom {
+	a.down().Put(level.Key(k), level.Value(v))
+	return a
+}
+
+//InterfaceAtom functions
+func (i *InterfaceAtom) atom() *Atom {
+	if i.Atom == nil {
+		i.Atom = new(Atom)
+	}
+	return i.Atom
+}
+
+/*
+	Delete the Value stored at the marshaled Key.
+*/
+func (i *InterfaceAtom) Delete(k KeyMarshaler) *InterfaceAtom {
+	i.atom().Delete(k.MarshalKey())
+	return i
+}
+
+/*
+	Store the marshaled Value at the marshaled Key.
+*/
+func (i *InterfaceAtom) Put(k KeyMarshaler, v ValueMarshaler) *InterfaceAtom {
+	i.atom().Put(k.MarshalKey(), v.MarshalValue())
+	return i
+}
+
+/*
+	Store a KeyValueMarshaler.
+*/
+func (i *InterfaceAtom) PutKV(kv KeyValueMarshaler) *InterfaceAtom {
+	return i.Put(kv, kv)
+}
+
+func (i *InterfaceAtom) Clear() *InterfaceAtom {
+	i.atom().Clear()
+	return i
+}
+
+func (i *InterfaceAtom) Close() *InterfaceAtom {
+	i.atom().Close()
+	return i
+}
+
+//Database functions
+func (d *Database) down() *level.Database {
+	return (*level.Database)(d)
+}
+
+func (d *Database) Inner() database {
+	return d.down().UnderlyingDatabase
+}
+
+/*
+	Open the Database stored at location.
+*/
+func (d *Database) Open(location string) error {
+	if err := lvl.OpenDatabase(d.down(), location); err != nil {
+		return err
+	}
+	d.down().SetNilOnNotFound(true)
+	return nil
+}
+
+/*
+	Close the Database, and its options.
+*/
+func (d *Database) Close() error {
+	return d.down().Close()
+}
+
+/*
+	Function SetOptions sets the Options of the Database. A Cache made for
+	them by SetCacheSize is adopted by the Database, and closed with it.
+*/
+func (d *Database) SetOptions(o *Options) *Database {
+	if o == nil {
+		o = new(Options)
+	}
+	d.down().SetOptions(o.down())
+	if c := sizedCache(o); c != nil {
+		if d.Cache == nil {
+			d.Cache = c.down()
+		} else {
+			//The Database's own Cache replaces it when opened.
+			c.down().Close()
+		}
+	}
+	return d
+}
+
+func (d *Database) SetReadOptions(r *ReadOptions) *Database {
+	d.ReadOptions = r.down()
+	return d
+}
+
+func (d *Database) SetWriteOptions(w *WriteOptions) *Database {
+	d.WriteOptions = w.down()
+	return d
+}
+
+/*
+	Gets a single value from the Database.
+	If there is no Value at Key, both the Value and error are nil.
+*/
+func (d *Database) Get(k Key) (Value, error) {
+	v, err := d.down().Get(level.Key(k))
+	return Value(v), err
+}
+
+/*
+	Puts a single value into the Database.
+	For batch puts, use an Atom.
+*/
+func (d *Database) Put(k Key, v Value) error {
+	return d.down().Put(level.Key(k), level.Value(v))
+}
+
+/*
+	Deletes a single value from the Database.
+	For batch deletions, use an Atom.
+*/
+func (d *Database) Delete(k Key) error {
+	return d.down().Delete(level.Key(k))
+}
+
+/*
+	Write an Atom to the Database.
+*/
+func (d *Database) Write(a *Atom) error {
+	return d.down().Write(a.down())
+}
+
+/*
+	Write an Atom to the Database, closing it afterward.
+*/
+func (d *Database) Commit(a *Atom) error {
+	return d.down().Commit(a.down())
+}
+
+/*
+	Write an InterfaceAtom to the Database, closing it afterward.
+*/
+func (d *Database) CommitInterface(i *InterfaceAtom) error {
+	return d.Commit(i.atom())
+}
@@ -0,0 +1,116 @@
+package legacy
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+//Run under both backends with:
+//	go test
+//	go test -tags purego
+func TestLegacy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db := new(Database).SetOptions(
+		new(Options).SetCreateIfMissing(
+			true,
+		).SetCacheSize(
+			Megabyte,
+		),
+	).SetReadOptions(
+		new(ReadOptions).SetVerifyChecksums(
+			true,
+		),
+	).SetWriteOptions(
+		new(WriteOptions).SetSync(
+			true,
+		),
+	)
+
+	//The cache made by SetCacheSize is closed with the Database.
+	if db.Cache == nil {
+		t.Fatal("Database did not adopt the cache of its Options")
+	}
+
+	if err = db.Open(dir); err != nil {
+		t.Fatal("Error whilst loading DB: ", err)
+	}
+	defer db.Close()
+
+	if err = db.Put(Key("one"), Value("1")); err != nil {
+		t.Fatal("Error storing value: ", err)
+	}
+
+	err = db.Commit(
+		new(Atom).Put(
+			Key("two"),
+			Value("2"),
+		).Delete(
+			Key("one"),
+		),
+	)
+	if err != nil {
+		t.Fatal("Error performing atomic DB write: ", err)
+	}
+
+	err = db.CommitInterface(
+		new(InterfaceAtom).PutKV(
+			pair{"three", "3"},
+		).Put(
+			Key("four"),
+			Value("4"),
+		).Delete(
+			Key("four"),
+		),
+	)
+	if err != nil {
+		t.Fatal("Error performing atomic DB write of an InterfaceAtom: ", err)
+	}
+
+	for k, want := range map[string]Value{
+		"one":   nil,
+		"two":   Value("2"),
+		"three": Value("3"),
+		"four":  nil,
+	} {
+		v, err := db.Get(Key(k))
+		if err != nil {
+			t.Fatal("Error retrieving ", k, ": ", err)
+		}
+		if !bytes.Equal(v, want) || (v == nil) != (want == nil) {
+			t.Fatalf("Value at %q is %q, expected %q", k, v, want)
+		}
+	}
+
+	if err = db.Delete(Key("two")); err != nil {
+		t.Fatal("Error deleting value: ", err)
+	}
+}
+
+type pair struct {
+	k, v string
+}
+
+func (p pair) MarshalKey() Key {
+	return Key(p.k)
+}
+
+func (p pair) MarshalValue() Value {
+	return Value(p.v)
+}
+
+func TestNilOptions(t *testing.T) {
+	db := new(Database).SetOptions(nil)
+	if db.Options == nil {
+		t.Fatal("Database given nil Options has none")
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal("Error closing unopened DB: ", err)
+	}
+}
@@ -7,5 +7,5 @@ import (
 )
 
 func init() {
-	lvl = lvg.Level()
+	lvl = lvg.Level
 }
@@ -0,0 +1,985 @@
+/*
+	Package leveltest provides a conformance suite for implementations of
+	level.UnderlyingLevel, so that a new backend, or a decorator of an
+	existing one, may show that it behaves like levigo and goleveldb.
+
+		func TestConformance(t *testing.T) {
+			leveltest.Run(t, memlevel.Level)
+		}
+
+	Each test opens its own database in a temporary directory, which is
+	destroyed afterward.
+*/
+package leveltest
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/TShadwell/level"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+/*
+	A Test exercises one behaviour of a *level.Level, using a database
+	stored at location.
+*/
+type Test func(t *testing.T, lvl *level.Level, location string)
+
+/*
+	Tests are the tests run by Run, by name.
+*/
+var Tests = []struct {
+	Name string
+	Test
+}{
+	{"NotFound", NotFound},
+	{"EmptyKeysAndValues", EmptyKeysAndValues},
+	{"BinaryKeys", BinaryKeys},
+	{"LargeValues", LargeValues},
+	{"AtomAtomicity", AtomAtomicity},
+	{"AtomOrdering", AtomOrdering},
+	{"AtomClear", AtomClear},
+	{"AtomIntrospection", AtomIntrospection},
+	{"AtomMarshaling", AtomMarshaling},
+	{"Reopen", Reopen},
+	{"CreateIfMissing", CreateIfMissing},
+	{"ErrorIfExists", ErrorIfExists},
+	{"Options", Options},
+	{"PerCallOptions", PerCallOptions},
+	{"Concurrency", Concurrency},
+	{"Comparator", Comparator},
+	{"Compaction", Compaction},
+	{"Properties", Properties},
+	{"DeleteRange", DeleteRange},
+	{"DeletePrefix", DeletePrefix},
+}
+
+/*
+	Function Run runs every test in Tests against lvl.
+*/
+func Run(t *testing.T, lvl *level.Level) {
+	dir, err := ioutil.TempDir("", "leveltest")
+	if err != nil {
+		t.Fatal("Could not create temporary directory: ", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, v := range Tests {
+		test, location := v.Test, filepath.Join(dir, v.Name)
+		t.Run(v.Name, func(t *testing.T) {
+			//Cleanups run last first, so this follows closing the database.
+			t.Cleanup(func() {
+				lvl.DestroyDatabase(location, nil)
+			})
+			test(t, lvl, location)
+		})
+	}
+}
+
+/*
+	Function Open opens a database at location, creating it if it is missing.
+	The database is closed, and then destroyed, when the test finishes.
+*/
+func Open(t *testing.T, lvl *level.Level, location string) *level.Database {
+	db := &level.Database{
+		Options: lvl.NewOptions().SetCreateIfMissing(
+			true,
+		),
+	}
+	if err := lvl.OpenDatabase(db, location); err != nil {
+		t.Fatal("Error whilst loading DB: ", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		lvl.DestroyDatabase(location, nil)
+	})
+	return db
+}
+
+func mustGet(t *testing.T, db *level.Database, k level.Key, want level.Value) {
+	v, err := db.Get(k)
+	if err != nil {
+		t.Fatalf("Error retrieving %q: %v", k, err)
+	}
+	if !bytes.Equal(v, want) {
+		t.Fatalf("Value at %q is %q, expected %q", k, v, want)
+	}
+}
+
+func mustMiss(t *testing.T, db *level.Database, k level.Key) {
+	if v, err := db.Get(k); err != level.ErrNotFound {
+		t.Fatalf("Expected ErrNotFound for %q, got %q, %v", k, v, err)
+	}
+}
+
+/*
+	Missing keys must return level.ErrNotFound.
+*/
+func NotFound(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	mustMiss(t, db, level.Key("missing"))
+
+	if err := db.Put(level.Key("k"), level.Value("v")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(level.Key("k")); err != nil {
+		t.Fatal(err)
+	}
+	mustMiss(t, db, level.Key("k"))
+
+	if err := db.Delete(level.Key("never stored")); err != nil {
+		t.Fatal("Deleting a missing key should not fail: ", err)
+	}
+}
+
+/*
+	Empty keys and values are ordinary keys and values.
+*/
+func EmptyKeysAndValues(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	if err := db.Put(level.Key{}, level.Value("empty key")); err != nil {
+		t.Fatal(err)
+	}
+	mustGet(t, db, level.Key{}, level.Value("empty key"))
+
+	if err := db.Put(level.Key("empty value"), level.Value{}); err != nil {
+		t.Fatal(err)
+	}
+	mustGet(t, db, level.Key("empty value"), level.Value{})
+
+	has, err := db.Has(level.Key("empty value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !has {
+		t.Fatal("A key with an empty value was reported missing")
+	}
+}
+
+/*
+	Keys are arbitrary bytes, and iterate in bytewise order.
+*/
+func BinaryKeys(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	keys := []level.Key{
+		{0x00},
+		{0x00, 0x00},
+		{0x00, 0xff},
+		{0x01},
+		{0x7f, 0x80},
+		{0xff},
+		{0xff, 0x00},
+		{0xff, 0xff, 0xff},
+	}
+
+	an := lvl.NewAtom()
+	//Insert in reverse, so that order must come from the database.
+	for i := len(keys) - 1; i >= 0; i-- {
+		an.Put(keys[i], level.Value{byte(i)})
+	}
+	if err := db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, k := range keys {
+		mustGet(t, db, k, level.Value{byte(i)})
+	}
+
+	it := db.NewIterator()
+	defer it.Close()
+
+	var i int
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		if i >= len(keys) {
+			t.Fatalf("Iterated past the last key, to %x", it.Key())
+		}
+		if !bytes.Equal(it.Key(), keys[i]) {
+			t.Fatalf("Iterated key %d is %x, expected %x", i, it.Key(), keys[i])
+		}
+		i++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if i != len(keys) {
+		t.Fatalf("Iterated over %d keys, expected %d", i, len(keys))
+	}
+
+	i = len(keys) - 1
+	for it.SeekToLast(); it.Valid(); it.Prev() {
+		if !bytes.Equal(it.Key(), keys[i]) {
+			t.Fatalf("Reverse iterated key %d is %x, expected %x", i, it.Key(), keys[i])
+		}
+		i--
+	}
+	if i != -1 {
+		t.Fatalf("Reverse iteration stopped at key %d", i)
+	}
+
+	it.Seek(level.Key{0x02})
+	if !it.Valid() || !bytes.Equal(it.Key(), level.Key{0x7f, 0x80}) {
+		t.Fatal("Seek did not move to the next key present")
+	}
+}
+
+/*
+	Values may be large.
+*/
+func LargeValues(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	large := make(level.Value, 4*level.Megabyte)
+	for i := range large {
+		large[i] = byte(i * 7)
+	}
+
+	if err := db.Put(level.Key("large"), large); err != nil {
+		t.Fatal(err)
+	}
+	mustGet(t, db, level.Key("large"), large)
+}
+
+/*
+	Readers must observe all of an Atom, or none of it.
+*/
+func AtomAtomicity(t *testing.T, lvl *level.Level, location string) {
+	const (
+		keys   = 16
+		rounds = 50
+	)
+	db := Open(t, lvl, location)
+
+	key := func(i int) level.Key {
+		return level.Key(fmt.Sprintf("atomic-%02d", i))
+	}
+
+	write := func(round int) error {
+		an := lvl.NewAtom()
+		for i := 0; i < keys; i++ {
+			an.Put(key(i), level.Value{byte(round)})
+		}
+		return db.Commit(an)
+	}
+
+	if err := write(0); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		for r := 1; r <= rounds; r++ {
+			if err := write(r); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	var failure error
+	for r := 0; r < rounds && failure == nil; r++ {
+		it := db.NewIterator()
+		var (
+			seen  int
+			round level.Value
+		)
+		for it.Seek(key(0)); it.Valid() && seen < keys; it.Next() {
+			if round == nil {
+				round = append(level.Value{}, it.Value()...)
+			} else if !bytes.Equal(it.Value(), round) {
+				failure = fmt.Errorf("observed a partially written Atom: %q is %v, expected %v", it.Key(), it.Value(), round)
+				break
+			}
+			seen++
+		}
+		if err := it.Err(); err != nil && failure == nil {
+			failure = err
+		}
+		it.Close()
+		if seen != keys && failure == nil {
+			failure = fmt.Errorf("observed %d keys of an Atom, expected %d", seen, keys)
+		}
+	}
+
+	//Wait for the writer before failing, so it does not outlive the database.
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if failure != nil {
+		t.Fatal(failure)
+	}
+}
+
+/*
+	The operations of an Atom apply in the order they were added.
+*/
+func AtomOrdering(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	err := db.Commit(
+		lvl.NewAtom().Put(
+			level.Key("a"),
+			level.Value("1"),
+		).Delete(
+			level.Key("a"),
+		).Delete(
+			level.Key("b"),
+		).Put(
+			level.Key("b"),
+			level.Value("1"),
+		).Put(
+			level.Key("b"),
+			level.Value("2"),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mustMiss(t, db, level.Key("a"))
+	mustGet(t, db, level.Key("b"), level.Value("2"))
+}
+
+type replayed []string
+
+func (r *replayed) Put(k level.Key, v level.Value) {
+	*r = append(*r, fmt.Sprintf("put %q %q", k, v))
+}
+
+func (r *replayed) Delete(k level.Key) {
+	*r = append(*r, fmt.Sprintf("delete %q", k))
+}
+
+/*
+	An Atom reports its operations, in order, and their size.
+*/
+func AtomIntrospection(t *testing.T, lvl *level.Level, location string) {
+	an := lvl.NewAtom()
+	defer an.Close()
+
+	if n := an.Len(); n != 0 {
+		t.Fatalf("New Atom has %d operations", n)
+	}
+	if s, err := an.ByteSize(); err != nil || s != 12 {
+		t.Fatalf("New Atom is %d bytes, expected 12: %v", s, err)
+	}
+
+	an.Put(
+		level.Key("a"),
+		level.Value("1"),
+	).Delete(
+		level.Key("b"),
+	).Put(
+		level.Key{0},
+		level.Value{},
+	)
+
+	if n := an.Len(); n != 3 {
+		t.Fatalf("Atom has %d operations, expected 3", n)
+	}
+
+	var r replayed
+	if err := an.Replay(&r); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`put "a" "1"`, `delete "b"`, `put "\x00" ""`}
+	if fmt.Sprint(r) != fmt.Sprint(want) {
+		t.Fatalf("Replayed %q, expected %q", r, want)
+	}
+
+	//Header, then tag and lengths around each key and value.
+	if s, err := an.ByteSize(); err != nil || s != 12+5+3+4 {
+		t.Fatalf("Atom is %d bytes, expected 24: %v", s, err)
+	}
+
+	str := `Atom{Put("a", "1"), Delete("b"), Put("\x00", "")}`
+	if an.String() != str {
+		t.Fatalf("Atom renders as %s, expected %s", an, str)
+	}
+
+	if n := an.Clear().Len(); n != 0 {
+		t.Fatalf("Cleared Atom has %d operations", n)
+	}
+}
+
+/*
+	An Atom is marshaled in LevelDB's write batch format, and
+	unmarshaling it gives an Atom with the same operations.
+*/
+func AtomMarshaling(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	an := lvl.NewAtom().Put(
+		level.Key("a"),
+		level.Value("1"),
+	).Delete(
+		level.Key("b"),
+	)
+	data, err := an.MarshalBinary()
+	an.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{
+		0, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0,
+		1, 1, 'a', 1, '1',
+		0, 1, 'b',
+	}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("Atom marshaled as %v, expected %v", data, want)
+	}
+
+	if err = db.Put(level.Key("b"), level.Value("2")); err != nil {
+		t.Fatal(err)
+	}
+
+	an = lvl.NewAtom().Put(level.Key("discarded"), level.Value{})
+	if err = an.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if n := an.Len(); n != 2 {
+		t.Fatalf("Unmarshaled Atom has %d operations, expected 2", n)
+	}
+	if err = db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+	mustGet(t, db, level.Key("a"), level.Value("1"))
+	mustMiss(t, db, level.Key("b"))
+	mustMiss(t, db, level.Key("discarded"))
+
+	an = lvl.NewAtom()
+	defer an.Close()
+	for _, corrupt := range [][]byte{
+		want[:11],
+		want[:len(want)-1],
+		append(append([]byte{}, want...), 7),
+		append(append([]byte{}, want[:8]...), append([]byte{3, 0, 0, 0}, want[12:]...)...),
+	} {
+		if err = an.UnmarshalBinary(corrupt); err != level.ErrCorruptAtom {
+			t.Fatalf("Unmarshaling %v gave %v, expected ErrCorruptAtom", corrupt, err)
+		}
+		if n := an.Len(); n != 0 {
+			t.Fatalf("Atom holds %d operations after failing to unmarshal", n)
+		}
+	}
+}
+
+/*
+	Clearing an Atom discards its operations, and it may be reused.
+*/
+func AtomClear(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	an := lvl.NewAtom().Put(
+		level.Key("discarded"),
+		level.Value("x"),
+	).Clear().Put(
+		level.Key("kept"),
+		level.Value("y"),
+	)
+
+	if err := db.Write(an); err != nil {
+		t.Fatal(err)
+	}
+	mustMiss(t, db, level.Key("discarded"))
+	mustGet(t, db, level.Key("kept"), level.Value("y"))
+
+	if err := db.Commit(an.Clear()); err != nil {
+		t.Fatal("Writing an empty Atom should not fail: ", err)
+	}
+	mustGet(t, db, level.Key("kept"), level.Value("y"))
+}
+
+/*
+	Writes persist across closing and reopening a database.
+*/
+func Reopen(t *testing.T, lvl *level.Level, location string) {
+	db := &level.Database{
+		Options: lvl.NewOptions().SetCreateIfMissing(
+			true,
+		),
+	}
+	if err := lvl.OpenDatabase(db, location); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(level.Key("persisted"), level.Value("yes")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Commit(lvl.NewAtom().Put(level.Key("atom"), level.Value("yes"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal("Error closing DB: ", err)
+	}
+
+	db = Open(t, lvl, location)
+	mustGet(t, db, level.Key("persisted"), level.Value("yes"))
+	mustGet(t, db, level.Key("atom"), level.Value("yes"))
+}
+
+/*
+	Opening a missing database fails unless it is to be created.
+*/
+func CreateIfMissing(t *testing.T, lvl *level.Level, location string) {
+	db := &level.Database{
+		Options: lvl.NewOptions().SetCreateIfMissing(
+			false,
+		),
+	}
+	if err := lvl.OpenDatabase(db, location); err == nil {
+		db.Close()
+		t.Fatal("Opened a missing database without SetCreateIfMissing")
+	}
+}
+
+/*
+	Opening an existing database fails if it must not exist.
+*/
+func ErrorIfExists(t *testing.T, lvl *level.Level, location string) {
+	db := &level.Database{
+		Options: lvl.NewOptions().SetCreateIfMissing(
+			true,
+		),
+	}
+	if err := lvl.OpenDatabase(db, location); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db = &level.Database{
+		Options: lvl.NewOptions().SetErrorIfExists(
+			true,
+		),
+	}
+	if err := lvl.OpenDatabase(db, location); err == nil {
+		db.Close()
+		t.Fatal("Opened an existing database with SetErrorIfExists")
+	}
+}
+
+/*
+	Reads and writes succeed with every option set.
+*/
+func Options(t *testing.T, lvl *level.Level, location string) {
+	db := &level.Database{
+		Cache:        lvl.NewCache(8 * level.Megabyte),
+		FilterPolicy: lvl.NewBloomFilter(10),
+		Options: lvl.NewOptions().SetCreateIfMissing(
+			true,
+		).SetParanoidChecks(
+			true,
+		).SetWriteBufferSize(
+			level.Megabyte,
+		).SetMaxOpenFiles(
+			100,
+		).SetBlockSize(
+			8 * level.Kilobyte,
+		).SetBlockRestartInterval(
+			8,
+		).SetCompression(
+			level.NoCompression,
+		).SetBloomFilter(
+			10,
+		),
+		ReadOptions: lvl.NewReadOptions().SetVerifyChecksums(
+			true,
+		),
+		WriteOptions: lvl.NewWriteOptions().SetSync(
+			true,
+		),
+	}
+	if err := lvl.OpenDatabase(db, location); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Put(level.Key("synced"), level.Value("yes")); err != nil {
+		t.Fatal(err)
+	}
+	mustGet(t, db, level.Key("synced"), level.Value("yes"))
+}
+
+/*
+	Reads and writes may be given their own options, without
+	changing those of the Database.
+*/
+func PerCallOptions(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	w := lvl.NewWriteOptions().SetSync(true)
+	defer w.Close()
+	r := lvl.NewReadOptions().SetVerifyChecksums(true).SetFillCache(false)
+	defer r.Close()
+
+	if err := db.PutWith(w, level.Key("a"), level.Value("1")); err != nil {
+		t.Fatal(err)
+	}
+	err := db.CommitWith(w, lvl.NewAtom().Put(level.Key("b"), level.Value("2")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := db.GetWith(r, level.Key("a"))
+	if err != nil || string(v) != "1" {
+		t.Fatalf("Value at \"a\" is %q, %v", v, err)
+	}
+	if has, err := db.HasWith(r, level.Key("b")); err != nil || !has {
+		t.Fatal("Key written with its own options is missing: ", err)
+	}
+
+	it := db.NewIteratorWith(r)
+	var n int
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		n++
+	}
+	if err = it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	it.Close()
+	if n != 2 {
+		t.Fatalf("Iterated over %d keys, expected 2", n)
+	}
+
+	if err = db.DeleteWith(w, level.Key("a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.GetWith(nil, level.Key("a")); err != level.ErrNotFound {
+		t.Fatal("Expected ErrNotFound for deleted key, got: ", err)
+	}
+}
+
+/*
+	A database may be used from many goroutines at once.
+*/
+func Concurrency(t *testing.T, lvl *level.Level, location string) {
+	const (
+		workers = 8
+		writes  = 100
+	)
+	db := Open(t, lvl, location)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				k := level.Key(fmt.Sprintf("%d-%d", w, i))
+				v := level.Value(fmt.Sprint(i))
+				if err := db.Put(k, v); err != nil {
+					errs <- err
+					return
+				}
+				got, err := db.Get(k)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if !bytes.Equal(got, v) {
+					errs <- fmt.Errorf("value at %q is %q, expected %q", k, got, v)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	it := db.NewIterator()
+	defer it.Close()
+	var n int
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		n++
+	}
+	if n != workers*writes {
+		t.Fatalf("Iterated over %d keys, expected %d", n, workers*writes)
+	}
+}
+
+/*
+	Compacting a range keeps the values within it, and removes those deleted.
+*/
+func Compaction(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	suspended := true
+	switch err := db.SuspendCompaction(); err {
+	case nil:
+	case level.ErrCompactionUnsupported:
+		suspended = false
+	default:
+		t.Fatal(err)
+	}
+
+	an := lvl.NewAtom()
+	for i := 0; i < 1000; i++ {
+		k := level.Key(fmt.Sprintf("compact-%04d", i))
+		an.Put(k, level.Value(k))
+		if i%2 == 0 {
+			an.Delete(k)
+		}
+	}
+	if err := db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+
+	if suspended {
+		if err := db.ResumeCompaction(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.CompactRange(level.Key("compact-0100"), level.Key("compact-0200")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CompactRange(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	mustMiss(t, db, level.Key("compact-0100"))
+	mustGet(t, db, level.Key("compact-0101"), level.Value("compact-0101"))
+}
+
+/*
+	The properties which LevelDB provides are present, and sizes are given
+	for each Range.
+*/
+func Properties(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	an := lvl.NewAtom()
+	for i := 0; i < 100; i++ {
+		an.Put(level.Key(fmt.Sprintf("property-%02d", i)), make(level.Value, 1024))
+	}
+	if err := db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{
+		level.PropertyStats,
+		level.PropertySSTables,
+		level.PropertyNumFilesAtLevel(0),
+	} {
+		if _, err := db.Property(name); err != nil {
+			t.Fatalf("Error retrieving property %q: %v", name, err)
+		}
+	}
+
+	if _, err := db.Property("leveltest.no-such-property"); err != level.ErrUnknownProperty {
+		t.Fatal("Expected ErrUnknownProperty, got: ", err)
+	}
+
+	if _, err := db.Stats(); err != nil {
+		t.Fatal(err)
+	}
+
+	sizes, err := db.ApproximateSizes([]level.Range{
+		{Start: level.Key("property-00"), Limit: level.Key("property-50")},
+		{Start: level.Key("property-50"), Limit: level.Key("property-99")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sizes) != 2 {
+		t.Fatalf("Got %d sizes for 2 Ranges", len(sizes))
+	}
+}
+
+/*
+	Deleting a range deletes exactly the Keys within it.
+*/
+func DeleteRange(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	const keys = 3000
+	key := func(i int) level.Key {
+		return level.Key(fmt.Sprintf("range-%04d", i))
+	}
+
+	an := lvl.NewAtom()
+	for i := 0; i < keys; i++ {
+		an.Put(key(i), level.Value{})
+	}
+	if err := db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+
+	//Span several chunks.
+	n, err := db.DeleteRange(key(100), key(2600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2500 {
+		t.Fatalf("Deleted %d keys, expected 2500", n)
+	}
+	mustGet(t, db, key(99), level.Value{})
+	mustMiss(t, db, key(100))
+	mustMiss(t, db, key(2599))
+	mustGet(t, db, key(2600), level.Value{})
+
+	if n, err = db.DeleteRange(key(2900), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n != 100 {
+		t.Fatalf("Deleted %d keys to the end, expected 100", n)
+	}
+	mustMiss(t, db, key(2999))
+
+	if n, err = db.DeleteRange(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n != 400 {
+		t.Fatalf("Deleted %d remaining keys, expected 400", n)
+	}
+}
+
+/*
+	Deleting a prefix through an Atom deletes exactly the Keys
+	beginning with it, once the Atom is written.
+*/
+func DeletePrefix(t *testing.T, lvl *level.Level, location string) {
+	db := Open(t, lvl, location)
+
+	keys := []level.Key{
+		level.Key("tenant-a"),
+		level.Key("tenant-a/1"),
+		level.Key("tenant-a/2"),
+		level.Key("tenant-b/1"),
+		{'t', 'e', 'n', 'a', 'n', 't', '-', 'a', 0xff},
+		level.Key("tenant-`"),
+	}
+	an := lvl.NewAtom()
+	for _, k := range keys {
+		an.Put(k, level.Value(k))
+	}
+	if err := db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+
+	an = lvl.NewAtom()
+	n, err := an.DeletePrefix(db, level.Key("tenant-a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Fatalf("Found %d keys under the prefix, expected 4", n)
+	}
+
+	//Nothing is deleted until the Atom is written.
+	mustGet(t, db, keys[0], level.Value(keys[0]))
+
+	if err = db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range keys[:3] {
+		mustMiss(t, db, k)
+	}
+	mustMiss(t, db, keys[4])
+	mustGet(t, db, keys[3], level.Value(keys[3]))
+	mustGet(t, db, keys[5], level.Value(keys[5]))
+}
+
+type reverse struct{}
+
+func (reverse) Compare(a, b level.Key) int {
+	return -bytes.Compare(a, b)
+}
+
+func (reverse) Name() string {
+	return "leveltest.Reverse"
+}
+
+/*
+	Keys are ordered by the Comparator, whose Name must match
+	when the database is reopened.
+*/
+func Comparator(t *testing.T, lvl *level.Level, location string) {
+	db := &level.Database{
+		Options: lvl.NewOptions().SetCreateIfMissing(
+			true,
+		).SetComparator(
+			reverse{},
+		),
+	}
+	switch err := lvl.OpenDatabase(db, location); err {
+	case nil:
+	case level.ErrComparatorUnsupported:
+		t.Skip("Implementation does not support Comparators")
+	default:
+		t.Fatal(err)
+	}
+
+	keys := []level.Key{
+		level.Key("c"),
+		level.Key("b"),
+		level.Key("a"),
+	}
+	an := lvl.NewAtom()
+	for _, k := range keys {
+		an.Put(k, level.Value(k))
+	}
+	if err := db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+
+	it := db.NewIterator()
+	var i int
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		if i >= len(keys) || !bytes.Equal(it.Key(), keys[i]) {
+			msg := fmt.Sprintf("Iterated key %d is %q, expected the order %q", i, it.Key(), keys)
+			it.Close()
+			t.Fatal(msg)
+		}
+		i++
+	}
+	it.Close()
+	if i != len(keys) {
+		t.Fatalf("Iterated over %d keys, expected %d", i, len(keys))
+	}
+
+	//Prefixes are found whatever the order of the Keys.
+	if err := db.Commit(lvl.NewAtom().Put(level.Key("ab"), nil).Put(level.Key("aa"), nil)); err != nil {
+		t.Fatal(err)
+	}
+	an = lvl.NewAtom()
+	n, err := an.DeletePrefix(db, level.Key("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("Found %d keys under the prefix, expected 3", n)
+	}
+	if err = db.Commit(an); err != nil {
+		t.Fatal(err)
+	}
+	mustMiss(t, db, level.Key("ab"))
+	mustGet(t, db, level.Key("b"), level.Value("b"))
+
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db = &level.Database{}
+	err = lvl.OpenDatabase(db, location)
+	if err == nil {
+		db.Close()
+		t.Fatal("Reopened a database with a mismatched Comparator")
+	}
+	if _, ok := err.(*level.ComparatorMismatchError); !ok {
+		t.Fatal("Expected a *ComparatorMismatchError, got: ", err)
+	}
+}
@@ -3,6 +3,7 @@ package level
 import (
 	"github.com/TShadwell/level"
 	"github.com/jmhodges/levigo"
+	"strings"
 )
 
 var Level *level.Level
@@ -11,74 +12,309 @@ type ulevel struct{}
 
 func init() {
 	Level = level.New(ulevel{})
+	level.Register("levigo", Level)
 }
 
 func (ulevel) NewLRUCache(capacity int) level.UnderlyingCache {
-	return levigo.NewLRUCache(capacity)
+	return che{levigo.NewLRUCache(capacity)}
+}
+
+func (ulevel) NewBloomFilter(bitsPerKey int) level.UnderlyingFilterPolicy {
+	return filt{levigo.NewBloomFilter(bitsPerKey)}
 }
 
 func (ulevel) DestroyDatabase(name string, o level.UnderlyingOptions) error {
-	return levigo.DestroyDatabase(name, o.(opts).Options)
+	return levigo.DestroyDatabase(name, o.(*opts).Options)
 }
 func (ulevel) RepairDatabase(name string, o level.UnderlyingOptions) error {
-	return levigo.RepairDatabase(name, o.(opts).Options)
+	return levigo.RepairDatabase(name, o.(*opts).Options)
 }
 func (ulevel) OpenDatabase(name string, o level.UnderlyingOptions) (level.UnderlyingDatabase, error) {
-	dtb, e := levigo.Open(name, o.(opts).Options)
-	return db{dtb}, e
+	op := o.(*opts)
+	//levigo does not expose LevelDB's comparator hooks.
+	if op.comparator != nil && op.comparator != level.BytewiseComparator {
+		return nil, level.ErrComparatorUnsupported
+	}
+	dtb, e := levigo.Open(name, op.Options)
+	if e != nil {
+		return nil, comparatorError(e)
+	}
+	return db{dtb}, nil
+}
+
+//comparatorError translates LevelDB's report of a mismatched comparator,
+//"<requested> does not match existing comparator <existing>".
+func comparatorError(e error) error {
+	const mismatch = " does not match existing comparator "
+	msg := e.Error()
+	i := strings.Index(msg, mismatch)
+	if i < 0 {
+		return e
+	}
+	requested := msg[:i]
+	if j := strings.LastIndex(requested, ": "); j >= 0 {
+		requested = requested[j+2:]
+	}
+	return &level.ComparatorMismatchError{
+		Existing:  msg[i+len(mismatch):],
+		Requested: requested,
+		Err:       e,
+	}
 }
 func (ulevel) NewOptions() level.UnderlyingOptions {
-	return opts{levigo.NewOptions()}
+	return &opts{Options: levigo.NewOptions()}
 }
 func (ulevel) NewReadOptions() level.UnderlyingReadOptions {
-	return levigo.NewReadOptions()
+	return ropts{levigo.NewReadOptions()}
 }
 func (ulevel) NewWriteOptions() level.UnderlyingWriteOptions {
-	return levigo.NewWriteOptions()
+	return wopts{levigo.NewWriteOptions()}
 }
 func (ulevel) NewWriteBatch() level.UnderlyingWriteBatch {
-	return wtb{levigo.NewWriteBatch()}
+	return new(wtb)
 }
 
 type db struct {
 	*levigo.DB
 }
 
+//levigo's Close functions cannot fail.
+func (d db) Close() error {
+	d.DB.Close()
+	return nil
+}
+
 func (d db) Delete(w level.UnderlyingWriteOptions, k level.Key) error {
-	return d.DB.Delete(w.(*levigo.WriteOptions), k)
+	return d.DB.Delete(w.(wopts).WriteOptions, k)
 }
 func (d db) Put(w level.UnderlyingWriteOptions, k level.Key, v level.Value) error {
-	return d.DB.Put(w.(*levigo.WriteOptions), k, v)
+	return d.DB.Put(w.(wopts).WriteOptions, k, v)
 }
 
 func (d db) Write(w level.UnderlyingWriteOptions, wb level.UnderlyingWriteBatch) error {
-	return d.DB.Write(w.(*levigo.WriteOptions), wb.(wtb).WriteBatch)
+	b := wb.(*wtb).batch()
+	defer b.Close()
+	return d.DB.Write(w.(wopts).WriteOptions, b)
 }
 
 func (d db) Get(r level.UnderlyingReadOptions, k level.Key) (level.Value, error) {
-	return d.DB.Get(r.(*levigo.ReadOptions), k)
+	v, e := d.DB.Get(r.(ropts).ReadOptions, k)
+	if e == nil && v == nil {
+		//levigo signals absence with a nil value
+		return nil, level.ErrNotFound
+	}
+	return v, e
+}
+
+func (d db) NewIterator(r level.UnderlyingReadOptions) level.UnderlyingIterator {
+	return itr{d.DB.NewIterator(r.(ropts).ReadOptions)}
+}
+
+func (d db) NewSnapshot() (level.UnderlyingSnapshot, error) {
+	return snap{d.DB.NewSnapshot(), d.DB}, nil
+}
+
+func (d db) CompactRange(start, limit level.Key) error {
+	d.DB.CompactRange(levigo.Range{Start: start, Limit: limit})
+	return nil
+}
+
+func (d db) Property(name string) (string, error) {
+	//LevelDB gives no value for properties which it does not know.
+	if v := d.DB.PropertyValue(name); v != "" {
+		return v, nil
+	}
+	return "", level.ErrUnknownProperty
+}
+
+func (d db) ApproximateSizes(r []level.Range) ([]uint64, error) {
+	rr := make([]levigo.Range, len(r))
+	for i, v := range r {
+		rr[i] = levigo.Range{Start: v.Start, Limit: v.Limit}
+	}
+	return d.DB.GetApproximateSizes(rr), nil
+}
+
+type snap struct {
+	*levigo.Snapshot
+	db *levigo.DB
+}
+
+func (s snap) Close() {
+	s.db.ReleaseSnapshot(s.Snapshot)
+}
+
+type ropts struct {
+	*levigo.ReadOptions
+}
+
+func (r ropts) Close() error {
+	r.ReadOptions.Close()
+	return nil
+}
+
+func (r ropts) SetSnapshot(s level.UnderlyingSnapshot) {
+	if s == nil {
+		r.ReadOptions.SetSnapshot(nil)
+		return
+	}
+	r.ReadOptions.SetSnapshot(s.(snap).Snapshot)
+}
+
+type wopts struct {
+	*levigo.WriteOptions
+}
+
+func (w wopts) Close() error {
+	w.WriteOptions.Close()
+	return nil
+}
+
+type itr struct {
+	*levigo.Iterator
+}
+
+func (i itr) Seek(k level.Key) {
+	i.Iterator.Seek(k)
+}
+
+func (i itr) Key() level.Key {
+	return i.Iterator.Key()
 }
 
+func (i itr) Value() level.Value {
+	return i.Iterator.Value()
+}
+
+func (i itr) Err() error {
+	return i.Iterator.GetError()
+}
+
+type op struct {
+	del bool
+	k   level.Key
+	v   level.Value
+}
+
+//LevelDB's C API cannot iterate a write batch, so rather than keep both
+//it and a copy of its operations, only the operations are kept, and
+//the write batch is made from them when the Atom is written.
 type wtb struct {
-	*levigo.WriteBatch
+	ops []op
+}
+
+func (w *wtb) Delete(k level.Key) {
+	w.ops = append(w.ops, op{true, append(level.Key{}, k...), nil})
+}
+
+func (w *wtb) Put(k level.Key, v level.Value) {
+	w.ops = append(w.ops, op{false, append(level.Key{}, k...), append(level.Value{}, v...)})
+}
+
+func (w *wtb) Clear() {
+	w.ops = w.ops[:0]
+}
+
+func (w *wtb) Close() {
+	w.ops = nil
 }
 
-func (w wtb) Delete(k level.Key) {
-	w.WriteBatch.Delete(k)
+func (w *wtb) Len() int {
+	return len(w.ops)
 }
 
-func (w wtb) Put(k level.Key, v level.Value) {
-	w.WriteBatch.Put(k, v)
+func (w *wtb) Replay(r level.AtomReplayer) error {
+	for _, o := range w.ops {
+		if o.del {
+			r.Delete(o.k)
+		} else {
+			r.Put(o.k, o.v)
+		}
+	}
+	return nil
+}
+
+//batch returns a levigo.WriteBatch of the operations, which must be closed.
+func (w *wtb) batch() *levigo.WriteBatch {
+	b := levigo.NewWriteBatch()
+	for _, o := range w.ops {
+		if o.del {
+			b.Delete(o.k)
+		} else {
+			b.Put(o.k, o.v)
+		}
+	}
+	return b
 }
 
 type opts struct {
 	*levigo.Options
+	//A filter policy made by SetBloomFilter must outlive the database,
+	//and be closed with the Options.
+	filter     *levigo.FilterPolicy
+	comparator level.Comparator
 }
 
 func (o *opts) U() *levigo.Options {
 	return o.Options
 }
 
-func (o opts) SetCache(c level.UnderlyingCache) {
-	o.U().SetCache(c.(*levigo.Cache))
+func (o *opts) SetCache(c level.UnderlyingCache) {
+	o.U().SetCache(c.(che).Cache)
+}
+
+func (o *opts) SetCompression(c level.Compression) {
+	switch c {
+	case level.NoCompression:
+		o.U().SetCompression(levigo.NoCompression)
+	case level.SnappyCompression:
+		o.U().SetCompression(levigo.SnappyCompression)
+	}
+}
+
+func (o *opts) SetBloomFilter(bitsPerKey int) {
+	f := levigo.NewBloomFilter(bitsPerKey)
+	o.U().SetFilterPolicy(f)
+	o.closeFilter()
+	o.filter = f
+}
+
+func (o *opts) SetFilterPolicy(f level.UnderlyingFilterPolicy) {
+	o.U().SetFilterPolicy(f.(filt).FilterPolicy)
+	o.closeFilter()
+}
+
+func (o *opts) SetComparator(c level.Comparator) {
+	o.comparator = c
+}
+
+func (o *opts) closeFilter() {
+	if o.filter != nil {
+		o.filter.Close()
+		o.filter = nil
+	}
+}
+
+func (o *opts) Close() error {
+	o.Options.Close()
+	o.closeFilter()
+	return nil
+}
+
+type filt struct {
+	*levigo.FilterPolicy
+}
+
+func (f filt) Close() error {
+	f.FilterPolicy.Close()
+	return nil
+}
+
+type che struct {
+	*levigo.Cache
+}
+
+func (c che) Close() error {
+	c.Cache.Close()
+	return nil
 }
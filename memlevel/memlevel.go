@@ -0,0 +1,578 @@
+/*
+	Package memlevel provides a *level.Level which stores its databases
+	entirely in memory, for fast and hermetic tests.
+
+	Databases are kept for the life of the process, keyed by the name they
+	were opened with, so that a database which is closed and reopened still
+	holds its contents until it is destroyed.
+
+		db := &level.Database{
+			Options: memlevel.Level.NewOptions().SetCreateIfMissing(
+				true,
+			),
+		}
+
+		if err := memlevel.Level.OpenDatabase(db, "test"); err != nil {
+			t.Fatal(err)
+		}
+*/
+package memlevel
+
+import (
+	"errors"
+	"github.com/TShadwell/level"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var Level *level.Level
+
+func init() {
+	Level = level.New(ulevel{})
+	level.Register("memlevel", Level)
+}
+
+var (
+	ErrLocked  = errors.New("memlevel: database is already open")
+	ErrMissing = errors.New("memlevel: database does not exist")
+	ErrExists  = errors.New("memlevel: database already exists")
+	ErrClosed  = errors.New("memlevel: database is closed")
+)
+
+//stores holds every database in the process, by name.
+var stores = struct {
+	sync.Mutex
+	m map[string]*store
+}{
+	m: make(map[string]*store),
+}
+
+type entry struct {
+	k level.Key
+	v level.Value
+}
+
+//entries is a slice of entries sorted by a Comparator.
+type entries []entry
+
+//search returns the index of the first entry at or after k,
+//and whether it is k.
+func (e entries) search(c level.Comparator, k level.Key) (int, bool) {
+	i := sort.Search(len(e), func(i int) bool {
+		return c.Compare(e[i].k, k) >= 0
+	})
+	return i, i < len(e) && c.Compare(e[i].k, k) == 0
+}
+
+func (e entries) get(c level.Comparator, k level.Key) (level.Value, bool) {
+	if i, ok := e.search(c, k); ok {
+		return e[i].v, true
+	}
+	return nil, false
+}
+
+type store struct {
+	sync.RWMutex
+	entries
+	cmp level.Comparator
+	//shared is set once entries has been captured by an Iterator or
+	//Snapshot, after which it must be copied before being modified.
+	shared bool
+	open   bool
+}
+
+//view returns the current entries, which must not be modified.
+func (s *store) view() entries {
+	s.Lock()
+	defer s.Unlock()
+	s.shared = true
+	return s.entries
+}
+
+//writable returns entries which may be modified in place. Must be called
+//with s locked.
+func (s *store) writable() entries {
+	if s.shared {
+		s.entries = append(entries(nil), s.entries...)
+		s.shared = false
+	}
+	return s.entries
+}
+
+func (s *store) put(k level.Key, v level.Value) {
+	e := s.writable()
+	i, ok := e.search(s.cmp, k)
+	nv := append(level.Value{}, v...)
+	if ok {
+		e[i].v = nv
+		return
+	}
+	e = append(e, entry{})
+	copy(e[i+1:], e[i:])
+	e[i] = entry{append(level.Key{}, k...), nv}
+	s.entries = e
+}
+
+func (s *store) delete(k level.Key) {
+	e := s.writable()
+	if i, ok := e.search(s.cmp, k); ok {
+		s.entries = append(e[:i], e[i+1:]...)
+	}
+}
+
+type ulevel struct{}
+
+func (ulevel) OpenDatabase(name string, o level.UnderlyingOptions) (level.UnderlyingDatabase, error) {
+	op := o.(*opts)
+	c := op.comparator
+	if c == nil {
+		c = level.BytewiseComparator
+	}
+
+	stores.Lock()
+	defer stores.Unlock()
+	s, ok := stores.m[name]
+	if !ok {
+		if !op.createIfMissing {
+			return nil, ErrMissing
+		}
+		s = &store{cmp: c}
+		stores.m[name] = s
+	} else if op.errorIfExists {
+		return nil, ErrExists
+	} else if s.cmp.Name() != c.Name() {
+		return nil, &level.ComparatorMismatchError{
+			Existing:  s.cmp.Name(),
+			Requested: c.Name(),
+		}
+	}
+	if s.open {
+		return nil, ErrLocked
+	}
+	s.open = true
+	return &db{store: s}, nil
+}
+
+func (ulevel) DestroyDatabase(name string, o level.UnderlyingOptions) error {
+	stores.Lock()
+	defer stores.Unlock()
+	if s, ok := stores.m[name]; ok {
+		if s.open {
+			return ErrLocked
+		}
+		delete(stores.m, name)
+	}
+	return nil
+}
+
+//In memory databases cannot be corrupted, so there is nothing to repair.
+func (ulevel) RepairDatabase(name string, o level.UnderlyingOptions) error {
+	stores.Lock()
+	defer stores.Unlock()
+	s, ok := stores.m[name]
+	if !ok {
+		return ErrMissing
+	}
+	if s.open {
+		return ErrLocked
+	}
+	return nil
+}
+
+func (ulevel) NewLRUCache(capacity int) level.UnderlyingCache {
+	return che{}
+}
+
+func (ulevel) NewBloomFilter(bitsPerKey int) level.UnderlyingFilterPolicy {
+	return filt{}
+}
+
+func (ulevel) NewOptions() level.UnderlyingOptions {
+	return new(opts)
+}
+
+func (ulevel) NewReadOptions() level.UnderlyingReadOptions {
+	return new(ropts)
+}
+
+func (ulevel) NewWriteOptions() level.UnderlyingWriteOptions {
+	return new(wopts)
+}
+
+func (ulevel) NewWriteBatch() level.UnderlyingWriteBatch {
+	return new(wb)
+}
+
+type db struct {
+	*store
+	//closed is set atomically, as Close may race with other calls.
+	closed int32
+}
+
+func (d *db) isClosed() bool {
+	return atomic.LoadInt32(&d.closed) != 0
+}
+
+func (d *db) Close() error {
+	if !atomic.CompareAndSwapInt32(&d.closed, 0, 1) {
+		return ErrClosed
+	}
+	stores.Lock()
+	d.store.open = false
+	stores.Unlock()
+	return nil
+}
+
+func (d *db) Put(w level.UnderlyingWriteOptions, k level.Key, v level.Value) error {
+	if d.isClosed() {
+		return ErrClosed
+	}
+	d.Lock()
+	d.put(k, v)
+	d.Unlock()
+	return nil
+}
+
+func (d *db) Delete(w level.UnderlyingWriteOptions, k level.Key) error {
+	if d.isClosed() {
+		return ErrClosed
+	}
+	d.Lock()
+	d.delete(k)
+	d.Unlock()
+	return nil
+}
+
+func (d *db) Write(w level.UnderlyingWriteOptions, a level.UnderlyingWriteBatch) error {
+	if d.isClosed() {
+		return ErrClosed
+	}
+	d.Lock()
+	defer d.Unlock()
+	for _, o := range a.(*wb).ops {
+		if o.del {
+			d.delete(o.k)
+		} else {
+			d.put(o.k, o.v)
+		}
+	}
+	return nil
+}
+
+func (d *db) DeleteRange(w level.UnderlyingWriteOptions, start, limit level.Key) (int, error) {
+	if d.isClosed() {
+		return 0, ErrClosed
+	}
+	d.Lock()
+	defer d.Unlock()
+	e := d.entries
+	i, _ := e.search(d.cmp, start)
+	j := len(e)
+	if limit != nil {
+		j, _ = e.search(d.cmp, limit)
+	}
+	if j <= i {
+		return 0, nil
+	}
+	if d.shared {
+		//Copy only the entries which are kept.
+		d.entries = append(append(make(entries, 0, len(e)-(j-i)), e[:i]...), e[j:]...)
+		d.shared = false
+	} else {
+		d.entries = append(e[:i], e[j:]...)
+	}
+	return j - i, nil
+}
+
+//read returns the entries which reads through r should observe.
+func (d *db) read(r level.UnderlyingReadOptions) entries {
+	if s := r.(*ropts).snapshot; s != nil {
+		return s.entries
+	}
+	return d.view()
+}
+
+func (d *db) Get(r level.UnderlyingReadOptions, k level.Key) (level.Value, error) {
+	if d.isClosed() {
+		return nil, ErrClosed
+	}
+	var (
+		v  level.Value
+		ok bool
+	)
+	if s := r.(*ropts).snapshot; s != nil {
+		v, ok = s.get(d.cmp, k)
+	} else {
+		d.RLock()
+		v, ok = d.get(d.cmp, k)
+		d.RUnlock()
+	}
+	if !ok {
+		return nil, level.ErrNotFound
+	}
+	return append(level.Value{}, v...), nil
+}
+
+func (d *db) NewIterator(r level.UnderlyingReadOptions) level.UnderlyingIterator {
+	if d.isClosed() {
+		return &iter{err: ErrClosed}
+	}
+	return &iter{
+		entries: d.read(r),
+		cmp:     d.cmp,
+		pos:     -1,
+	}
+}
+
+func (d *db) NewSnapshot() (level.UnderlyingSnapshot, error) {
+	if d.isClosed() {
+		return nil, ErrClosed
+	}
+	return &snap{d.view()}, nil
+}
+
+//Memory is not compacted, so there is no space to reclaim and
+//no background work to suspend.
+
+func (d *db) CompactRange(start, limit level.Key) error {
+	if d.isClosed() {
+		return ErrClosed
+	}
+	return nil
+}
+
+func (d *db) SuspendCompaction() error {
+	return nil
+}
+
+func (d *db) ResumeCompaction() error {
+	return nil
+}
+
+//memStats has the header of LevelDB's stats, without levels.
+const memStats = `                               Compactions
+Level  Files Size(MB) Time(sec) Read(MB) Write(MB)
+--------------------------------------------------
+`
+
+func (d *db) Property(name string) (string, error) {
+	if d.isClosed() {
+		return "", ErrClosed
+	}
+	switch {
+	case name == level.PropertyStats:
+		return memStats, nil
+	case name == level.PropertySSTables:
+		return "", nil
+	case strings.HasPrefix(name, "leveldb.num-files-at-level"):
+		n, err := strconv.Atoi(strings.TrimPrefix(name, "leveldb.num-files-at-level"))
+		if err == nil && n >= 0 {
+			return "0", nil
+		}
+	}
+	return "", level.ErrUnknownProperty
+}
+
+//The approximate size of a Range in memory is that of its Keys and Values.
+func (d *db) ApproximateSizes(r []level.Range) ([]uint64, error) {
+	if d.isClosed() {
+		return nil, ErrClosed
+	}
+	e := d.view()
+	sizes := make([]uint64, len(r))
+	for i, v := range r {
+		start, _ := e.search(d.cmp, v.Start)
+		limit, _ := e.search(d.cmp, v.Limit)
+		for j := start; j < limit; j++ {
+			sizes[i] += uint64(len(e[j].k) + len(e[j].v))
+		}
+	}
+	return sizes, nil
+}
+
+type snap struct {
+	entries
+}
+
+func (s *snap) Close() {
+	s.entries = nil
+}
+
+type iter struct {
+	entries
+	cmp level.Comparator
+	pos int
+	err error
+}
+
+func (i *iter) Valid() bool {
+	return i.err == nil && i.pos >= 0 && i.pos < len(i.entries)
+}
+
+func (i *iter) Seek(k level.Key) {
+	i.pos, _ = i.search(i.cmp, k)
+}
+
+func (i *iter) SeekToFirst() {
+	i.pos = 0
+}
+
+func (i *iter) SeekToLast() {
+	i.pos = len(i.entries) - 1
+}
+
+func (i *iter) Next() {
+	if i.Valid() {
+		i.pos++
+	}
+}
+
+func (i *iter) Prev() {
+	if i.Valid() {
+		i.pos--
+	}
+}
+
+func (i *iter) Key() level.Key {
+	if !i.Valid() {
+		return nil
+	}
+	return i.entries[i.pos].k
+}
+
+func (i *iter) Value() level.Value {
+	if !i.Valid() {
+		return nil
+	}
+	return i.entries[i.pos].v
+}
+
+func (i *iter) Err() error {
+	return i.err
+}
+
+func (i *iter) Close() {
+	i.entries = nil
+}
+
+type op struct {
+	del bool
+	k   level.Key
+	v   level.Value
+}
+
+type wb struct {
+	ops []op
+}
+
+func (w *wb) Put(k level.Key, v level.Value) {
+	w.ops = append(w.ops, op{false, append(level.Key{}, k...), append(level.Value{}, v...)})
+}
+
+func (w *wb) Delete(k level.Key) {
+	w.ops = append(w.ops, op{true, append(level.Key{}, k...), nil})
+}
+
+func (w *wb) Clear() {
+	w.ops = w.ops[:0]
+}
+
+func (w *wb) Close() {
+	w.ops = nil
+}
+
+func (w *wb) Len() int {
+	return len(w.ops)
+}
+
+func (w *wb) Replay(r level.AtomReplayer) error {
+	for _, o := range w.ops {
+		if o.del {
+			r.Delete(o.k)
+		} else {
+			r.Put(o.k, o.v)
+		}
+	}
+	return nil
+}
+
+type opts struct {
+	createIfMissing bool
+	errorIfExists   bool
+	comparator      level.Comparator
+}
+
+func (o *opts) SetCreateIfMissing(b bool) {
+	o.createIfMissing = b
+}
+
+func (o *opts) SetErrorIfExists(b bool) {
+	o.errorIfExists = b
+}
+
+func (o *opts) SetComparator(c level.Comparator) {
+	o.comparator = c
+}
+
+//The remaining options concern data on disk, and so are ignored.
+
+func (o *opts) SetParanoidChecks(bool)                       {}
+func (o *opts) SetCache(level.UnderlyingCache)               {}
+func (o *opts) SetWriteBufferSize(int)                       {}
+func (o *opts) SetMaxOpenFiles(int)                          {}
+func (o *opts) SetBlockSize(int)                             {}
+func (o *opts) SetBlockRestartInterval(int)                  {}
+func (o *opts) SetCompression(level.Compression)             {}
+func (o *opts) SetBloomFilter(int)                           {}
+func (o *opts) SetFilterPolicy(level.UnderlyingFilterPolicy) {}
+
+func (o *opts) Close() error {
+	return nil
+}
+
+type ropts struct {
+	snapshot *snap
+}
+
+//Checksums are not kept in memory.
+func (r *ropts) SetVerifyChecksums(bool) {}
+
+//There is no cache to fill.
+func (r *ropts) SetFillCache(bool) {}
+
+func (r *ropts) SetSnapshot(s level.UnderlyingSnapshot) {
+	if s == nil {
+		r.snapshot = nil
+		return
+	}
+	r.snapshot = s.(*snap)
+}
+
+func (r *ropts) Close() error {
+	r.snapshot = nil
+	return nil
+}
+
+//Writes to memory are as durable as they can be.
+type wopts struct{}
+
+func (*wopts) SetSync(bool) {}
+
+func (*wopts) Close() error {
+	return nil
+}
+
+type che struct{}
+
+func (che) Close() error {
+	return nil
+}
+
+type filt struct{}
+
+func (filt) Close() error {
+	return nil
+}
@@ -0,0 +1,10 @@
+package memlevel
+
+import (
+	"github.com/TShadwell/level/leveltest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	leveltest.Run(t, Level)
+}
@@ -0,0 +1,179 @@
+package level
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var levels = struct {
+	sync.RWMutex
+	m map[string]*Level
+}{
+	m: make(map[string]*Level),
+}
+
+/*
+	Function Register makes a *Level available to Open by name.
+	Implementations register themselves when their package is imported,
+	as with database/sql. Registering the same name twice panics.
+*/
+func Register(name string, l *Level) {
+	levels.Lock()
+	defer levels.Unlock()
+	if l == nil {
+		panic("level: Register of nil Level")
+	}
+	if _, dup := levels.m[name]; dup {
+		panic("level: Register called twice for " + name)
+	}
+	levels.m[name] = l
+}
+
+/*
+	Function Levels returns the sorted names of the registered Levels.
+*/
+func Levels() []string {
+	levels.RLock()
+	defer levels.RUnlock()
+	names := make([]string, 0, len(levels.m))
+	for name := range levels.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+	Function Lookup returns the Level registered under name, if any.
+*/
+func Lookup(name string) (l *Level, ok bool) {
+	levels.RLock()
+	defer levels.RUnlock()
+	l, ok = levels.m[name]
+	return
+}
+
+/*
+	Function Open opens a Database described by a URL, whose scheme names
+	a registered Level and whose path is the location of the Database.
+
+		db, err := level.Open("golevel:///var/data/db?create=1&cache=64MB")
+
+	The query may set:
+
+		create	create the Database if it is missing (bool)
+		cache	the size of the LRU cache, such as 64MB (size)
+		bloom	the bits per key of a bloom filter (int)
+		verify	verify the checksums of reads (bool)
+		sync	flush writes to disk immediately (bool)
+
+	Sizes are a number of bytes, optionally followed by B, KB, MB or GB.
+*/
+func Open(dsn string) (d *Database, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return
+	}
+
+	l, ok := Lookup(u.Scheme)
+	if !ok {
+		return nil, fmt.Errorf("level: unknown Level %q (forgotten import?)", u.Scheme)
+	}
+
+	location := u.Opaque
+	if location == "" {
+		location = u.Host + u.Path
+	}
+	if location == "" {
+		return nil, fmt.Errorf("level: no location in %q", dsn)
+	}
+
+	d = &Database{
+		Options:      l.NewOptions(),
+		ReadOptions:  l.NewReadOptions(),
+		WriteOptions: l.NewWriteOptions(),
+	}
+
+	if err = d.configure(l, u.Query()); err == nil {
+		err = l.OpenDatabase(d, location)
+	}
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	return
+}
+
+//configure applies the query parameters of a URL passed to Open.
+func (d *Database) configure(l *Level, q url.Values) (err error) {
+	for param, values := range q {
+		v := values[len(values)-1]
+		switch param {
+		case "create", "verify", "sync":
+			var b bool
+			if b, err = strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("level: %s: %v", param, err)
+			}
+			switch param {
+			case "create":
+				d.Options.SetCreateIfMissing(b)
+			case "verify":
+				d.ReadOptions.SetVerifyChecksums(b)
+			case "sync":
+				d.WriteOptions.SetSync(b)
+			}
+		case "cache":
+			var size BytesSize
+			if size, err = ParseBytesSize(v); err != nil {
+				return fmt.Errorf("level: %s: %v", param, err)
+			}
+			d.Cache = l.NewCache(size)
+		case "bloom":
+			var bits int
+			if bits, err = strconv.Atoi(v); err != nil {
+				return fmt.Errorf("level: %s: %v", param, err)
+			}
+			d.FilterPolicy = l.NewBloomFilter(bits)
+		default:
+			return fmt.Errorf("level: unknown parameter %q", param)
+		}
+	}
+	return
+}
+
+/*
+	Function ParseBytesSize parses a number of bytes, optionally followed
+	by one of the units B, KB, MB or GB, such as "64MB".
+*/
+func ParseBytesSize(s string) (BytesSize, error) {
+	t := strings.ToUpper(strings.TrimSpace(s))
+	unit := uint64(Byte)
+	for _, u := range []struct {
+		suffix string
+		size   uint64
+	}{
+		{"GB", Gigabyte},
+		{"MB", Megabyte},
+		{"KB", Kilobyte},
+		{"B", Byte},
+	} {
+		if strings.HasSuffix(t, u.suffix) {
+			t, unit = strings.TrimSpace(strings.TrimSuffix(t, u.suffix)), u.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseUint(t, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	if n > math.MaxUint64/unit || uint64(BytesSize(n*unit)) != n*unit {
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+	return BytesSize(n * unit), nil
+}
@@ -1,8 +1,14 @@
 package level
 
+/*
+	Function NewOptions returns Options for opening a Database.
+	Not every implementation makes use of every option. goleveldb and levigo
+	support them all; memlevel honours only SetCreateIfMissing and SetErrorIfExists,
+	as the others concern how data is laid out on disk.
+*/
 func (l *Level) NewOptions() *Options {
 	return &Options{
-		l.UnderlyingLevel.NewOptions(),
+		UnderlyingOptions: l.UnderlyingLevel.NewOptions(),
 	}
 }
 
@@ -15,6 +21,24 @@ func (o *Options) SetCreateIfMissing(yes bool) *Options {
 	return o
 }
 
+/*
+	Function SetErrorIfExists causes an attempt
+	to open a UnderlyingDatabase to fail if it already exists.
+*/
+func (o *Options) SetErrorIfExists(yes bool) *Options {
+	o.UnderlyingOptions.SetErrorIfExists(yes)
+	return o
+}
+
+/*
+	Function SetParanoidChecks causes the UnderlyingDatabase to check
+	aggressively for corruption, failing early if any is found.
+*/
+func (o *Options) SetParanoidChecks(yes bool) *Options {
+	o.UnderlyingOptions.SetParanoidChecks(yes)
+	return o
+}
+
 /*
 	Function SetCache sets the cache object for the UnderlyingDatabase
 */
@@ -22,3 +46,69 @@ func (o *Options) SetCache(c *Cache) *Options {
 	o.UnderlyingOptions.SetCache(c.UnderlyingCache)
 	return o
 }
+
+/*
+	Function SetWriteBufferSize sets the amount of data to build up in
+	memory before it is written to disk. Larger buffers improve bulk
+	loads, at the cost of memory and a longer recovery when opening.
+*/
+func (o *Options) SetWriteBufferSize(size BytesSize) *Options {
+	o.UnderlyingOptions.SetWriteBufferSize(int(size))
+	return o
+}
+
+/*
+	Function SetMaxOpenFiles sets the number of files which the
+	UnderlyingDatabase may keep open at once.
+*/
+func (o *Options) SetMaxOpenFiles(n int) *Options {
+	o.UnderlyingOptions.SetMaxOpenFiles(n)
+	return o
+}
+
+/*
+	Function SetBlockSize sets the approximate size of the
+	uncompressed data packed into each block.
+*/
+func (o *Options) SetBlockSize(size BytesSize) *Options {
+	o.UnderlyingOptions.SetBlockSize(int(size))
+	return o
+}
+
+/*
+	Function SetBlockRestartInterval sets the number of keys between
+	restart points for the delta encoding of keys within a block.
+*/
+func (o *Options) SetBlockRestartInterval(n int) *Options {
+	o.UnderlyingOptions.SetBlockRestartInterval(n)
+	return o
+}
+
+/*
+	Function SetCompression sets the Compression applied to blocks.
+*/
+func (o *Options) SetCompression(c Compression) *Options {
+	o.UnderlyingOptions.SetCompression(c)
+	return o
+}
+
+/*
+	Function SetBloomFilter causes the UnderlyingDatabase to keep a bloom
+	filter of the Keys in each block, using about bitsPerKey bits for each,
+	which spares reading blocks from disk for Keys which are not present.
+	A bitsPerKey of 10 gives about a one percent false positive rate.
+	The filter belongs to the Options, and is closed with them; to share
+	one between Databases, use SetFilterPolicy.
+*/
+func (o *Options) SetBloomFilter(bitsPerKey int) *Options {
+	o.UnderlyingOptions.SetBloomFilter(bitsPerKey)
+	return o
+}
+
+/*
+	Function SetFilterPolicy sets the FilterPolicy of the UnderlyingDatabase.
+*/
+func (o *Options) SetFilterPolicy(f *FilterPolicy) *Options {
+	o.UnderlyingOptions.SetFilterPolicy(f.UnderlyingFilterPolicy)
+	return o
+}
@@ -0,0 +1,143 @@
+package level
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+//The names of properties which every implementation backed by LevelDB provides.
+const (
+	//A table of the files, size and compaction work at each level,
+	//which may be parsed with ParseStats.
+	PropertyStats = "leveldb.stats"
+	//A description of the tables which make up the Database.
+	PropertySSTables = "leveldb.sstables"
+)
+
+/*
+	Function PropertyNumFilesAtLevel returns the name of the property
+	holding the number of files at level n.
+*/
+func PropertyNumFilesAtLevel(n int) string {
+	return "leveldb.num-files-at-level" + strconv.Itoa(n)
+}
+
+/*
+	A Range of Keys, from Start up to but not including Limit.
+*/
+type Range struct {
+	Start, Limit Key
+}
+
+/*
+	Function Property returns the value of a named property of the
+	Database, such as PropertyStats. If the implementation does
+	not know of the property, ErrUnknownProperty is returned.
+*/
+func (d *Database) Property(name string) (string, error) {
+	return d.UnderlyingDatabase.Property(name)
+}
+
+/*
+	Function ApproximateSizes returns the approximate space used on disk
+	by each of the Ranges, in bytes. Recent writes which have not
+	yet been compacted may not be counted.
+*/
+func (d *Database) ApproximateSizes(r []Range) ([]uint64, error) {
+	return d.UnderlyingDatabase.ApproximateSizes(r)
+}
+
+/*
+	Function Stats returns the parsed PropertyStats of the Database.
+*/
+func (d *Database) Stats() (*Stats, error) {
+	s, err := d.Property(PropertyStats)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStats(s), nil
+}
+
+/*
+	Stats describe the levels of a Database.
+*/
+type Stats struct {
+	Levels []LevelStats
+}
+
+/*
+	LevelStats describe the files at one level of a Database,
+	and the compaction work done on them.
+*/
+type LevelStats struct {
+	Level, Files int
+	Size         BytesSize
+	Time         time.Duration
+	Read, Write  BytesSize
+}
+
+/*
+	Function Files returns the total number of files in the Database.
+*/
+func (s *Stats) Files() (n int) {
+	for _, l := range s.Levels {
+		n += l.Files
+	}
+	return
+}
+
+/*
+	Function Size returns the total size of the files in the Database.
+*/
+func (s *Stats) Size() (n BytesSize) {
+	for _, l := range s.Levels {
+		n += l.Size
+	}
+	return
+}
+
+/*
+	Function ParseStats parses the value of PropertyStats. Both LevelDB and
+	goleveldb give one row for each level, of the level, number of files,
+	size in megabytes, compaction time in seconds, and megabytes read and
+	written by compaction; any other lines are ignored.
+*/
+func ParseStats(s string) *Stats {
+	stats := new(Stats)
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == '|' || r == ' ' || r == '\t'
+		})
+		if len(fields) != 6 {
+			continue
+		}
+
+		var (
+			l   LevelStats
+			num [4]float64
+			err error
+		)
+		if l.Level, err = strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		if l.Files, err = strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		for i := range num {
+			if num[i], err = strconv.ParseFloat(fields[i+2], 64); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			continue
+		}
+
+		l.Size = BytesSize(num[0] * Megabyte)
+		l.Time = time.Duration(num[1] * float64(time.Second))
+		l.Read = BytesSize(num[2] * Megabyte)
+		l.Write = BytesSize(num[3] * Megabyte)
+		stats.Levels = append(stats.Levels, l)
+	}
+	return stats
+}
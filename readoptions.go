@@ -10,3 +10,13 @@ func (r *ReadOptions) SetVerifyChecksums(yes bool) *ReadOptions {
 	r.UnderlyingReadOptions.SetVerifyChecksums(yes)
 	return r
 }
+
+/*
+	Function SetFillCache sets whether blocks read with these ReadOptions
+	are kept in the Cache. It is true by default; bulk scans may unset it
+	so as not to push out the blocks which other reads use.
+*/
+func (r *ReadOptions) SetFillCache(yes bool) *ReadOptions {
+	r.UnderlyingReadOptions.SetFillCache(yes)
+	return r
+}
@@ -0,0 +1,69 @@
+package level
+
+/*
+	Function NewSnapshot returns a Snapshot of the current state of the Database.
+	The Snapshot has its own ReadOptions, and must be closed to release it.
+*/
+func (d *Database) NewSnapshot() (s *Snapshot, err error) {
+	var us UnderlyingSnapshot
+	if us, err = d.UnderlyingDatabase.NewSnapshot(); err != nil {
+		return
+	}
+
+	ro := d.level.NewReadOptions()
+	ro.UnderlyingReadOptions.SetSnapshot(us)
+
+	s = &Snapshot{
+		us,
+		ro,
+		d,
+	}
+	return
+}
+
+/*
+	Function SetSnapshot causes reads made with these ReadOptions
+	to be made against the passed Snapshot. A nil Snapshot
+	causes reads to be made against the current state of the Database.
+*/
+func (r *ReadOptions) SetSnapshot(s *Snapshot) *ReadOptions {
+	if s == nil {
+		r.UnderlyingReadOptions.SetSnapshot(nil)
+	} else {
+		r.UnderlyingReadOptions.SetSnapshot(s.UnderlyingSnapshot)
+	}
+	return r
+}
+
+/*
+	Gets a single value from the Database as it was when the Snapshot was taken.
+*/
+func (s *Snapshot) Get(k Key) (Value, error) {
+	return s.db.get(s.ReadOptions, k)
+}
+
+/*
+	Function NewIterator returns an Iterator over the Database as it
+	was when the Snapshot was taken.
+*/
+func (s *Snapshot) NewIterator() *Iterator {
+	return &Iterator{
+		s.db.UnderlyingDatabase.NewIterator(s.ReadOptions.UnderlyingReadOptions),
+	}
+}
+
+/*
+	Function Close releases the Snapshot and its ReadOptions.
+	Iterators obtained from the Snapshot should be closed first.
+*/
+func (s *Snapshot) Close() error {
+	if s == nil {
+		return nil
+	}
+	err := s.ReadOptions.Close()
+	if s.UnderlyingSnapshot != nil {
+		s.UnderlyingSnapshot.Close()
+		s.UnderlyingSnapshot = nil
+	}
+	return err
+}
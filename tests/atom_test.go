@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/TShadwell/level"
+	glvl "github.com/TShadwell/level/golevel"
+	lvigo "github.com/TShadwell/level/levigo"
+	"github.com/TShadwell/level/memlevel"
+	"testing"
+)
+
+//An Atom marshaled by any implementation may be unmarshaled by any other.
+func TestAtomPortability(t *testing.T) {
+	levels := []*level.Level{glvl.Level, lvigo.Level, memlevel.Level}
+	for _, from := range levels {
+		an := from.NewAtom().Put(
+			keyone,
+			valueone,
+		).Delete(
+			keytwo,
+		)
+		data, err := an.MarshalBinary()
+		an.Close()
+		if err != nil {
+			t.Fatal("Error marshaling atom: ", err)
+		}
+
+		for _, to := range levels {
+			an := to.NewAtom()
+			if err = an.UnmarshalBinary(data); err != nil {
+				t.Fatal("Error unmarshaling atom: ", err)
+			}
+			again, err := an.MarshalBinary()
+			if err != nil {
+				t.Fatal("Error remarshaling atom: ", err)
+			}
+			if !bytes.Equal(data, again) {
+				t.Fatalf("Atom marshaled as %v, remarshaled as %v", data, again)
+			}
+			if s := an.String(); s != `Atom{Put("Alpha", "x"), Delete("Beta")}` {
+				t.Fatal("Unmarshaled atom differs: ", s)
+			}
+			an.Close()
+		}
+	}
+}
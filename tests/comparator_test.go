@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/TShadwell/level"
+	"testing"
+)
+
+func TestBytewiseComparator(t *testing.T) {
+	c := level.BytewiseComparator
+
+	for _, v := range []struct {
+		start, limit, separator level.Key
+	}{
+		{level.Key("abcdef"), level.Key("abzz"), level.Key("abd")},
+		{level.Key("abc"), level.Key("abcd"), level.Key("abc")},
+		{level.Key("abc"), level.Key("abd"), level.Key("abc")},
+		{level.Key{0xff, 0x01}, level.Key{0xff, 0x05}, level.Key{0xff, 0x02}},
+	} {
+		s := c.Separator(v.start, v.limit)
+		if !bytes.Equal(s, v.separator) {
+			t.Fatalf("Separator of %q and %q is %q, expected %q", v.start, v.limit, s, v.separator)
+		}
+		if c.Compare(s, v.start) < 0 || c.Compare(s, v.limit) >= 0 {
+			t.Fatalf("Separator %q is not between %q and %q", s, v.start, v.limit)
+		}
+	}
+
+	for _, v := range []struct {
+		k, successor level.Key
+	}{
+		{level.Key("abc"), level.Key("b")},
+		{level.Key{0xff, 0xff, 0x01}, level.Key{0xff, 0xff, 0x02}},
+		{level.Key{0xff, 0xff}, level.Key{0xff, 0xff}},
+	} {
+		if s := c.Successor(v.k); !bytes.Equal(s, v.successor) {
+			t.Fatalf("Successor of %x is %x, expected %x", v.k, s, v.successor)
+		}
+	}
+}
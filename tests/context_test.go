@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/TShadwell/level"
+	"github.com/TShadwell/level/leveltest"
+	"github.com/TShadwell/level/memlevel"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//stalled blocks reads until it is released.
+type stalled struct {
+	level.UnderlyingDatabase
+	release chan struct{}
+	started *int32
+}
+
+func (s stalled) Get(r level.UnderlyingReadOptions, k level.Key) (level.Value, error) {
+	if s.started != nil {
+		atomic.AddInt32(s.started, 1)
+	}
+	<-s.release
+	return s.UnderlyingDatabase.Get(r, k)
+}
+
+func TestContext(t *testing.T) {
+	lvl := memlevel.Level
+	db := leveltest.Open(t, lvl, "context")
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := db.PutContext(cancelled, keyone, valueone); err != context.Canceled {
+		t.Fatal("Expected Canceled from a cancelled Put, got: ", err)
+	}
+	if _, err := db.GetContext(context.Background(), keyone); err != level.ErrNotFound {
+		t.Fatal("Cancelled Put was made: ", err)
+	}
+	if err := db.CommitContext(cancelled, lvl.NewAtom().Put(keyone, valueone)); err != context.Canceled {
+		t.Fatal("Expected Canceled from a cancelled Commit, got: ", err)
+	}
+
+	an := lvl.NewAtom()
+	for i := 0; i < 1000; i++ {
+		an.Put(level.Key(fmt.Sprintf("%04d", i)), valueone)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := db.WriteContext(ctx, an); err != nil {
+		t.Fatal("Error writing with a live context: ", err)
+	}
+	an.Close()
+
+	//Iteration stops once the context is done.
+	it := db.NewIteratorContext(ctx)
+	var n int
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		if n++; n == 10 {
+			cancel()
+		}
+	}
+	if err := it.Err(); err != context.Canceled {
+		t.Fatal("Expected Canceled from a cancelled iteration, got: ", err)
+	}
+	it.Close()
+	if n < 10 || n > 10+level.IteratorContextChunk {
+		t.Fatalf("Cancelled iteration saw %d keys", n)
+	}
+
+	if _, err := db.DeleteRangeContext(cancelled, nil, nil); err != context.Canceled {
+		t.Fatal("Expected Canceled from a cancelled DeleteRange, got: ", err)
+	}
+
+	//A stalled read gives up at its deadline, and Close waits for it.
+	s := stalled{db.UnderlyingDatabase, make(chan struct{}), nil}
+	db.UnderlyingDatabase = s
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := db.GetContext(ctx, keyone); err != context.DeadlineExceeded {
+		t.Fatal("Expected DeadlineExceeded from a stalled Get, got: ", err)
+	}
+
+	closed := make(chan error)
+	go func() {
+		closed <- db.Close()
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close did not wait for a stalled Get")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(s.release)
+	if err := <-closed; err != nil {
+		t.Fatal("Error closing DB: ", err)
+	}
+}
+
+func TestContextWorkers(t *testing.T) {
+	defer func(n int) {
+		level.ContextWorkers = n
+	}(level.ContextWorkers)
+	level.ContextWorkers = 2
+
+	db := leveltest.Open(t, memlevel.Level, "contextworkers")
+
+	var started int32
+	s := stalled{db.UnderlyingDatabase, make(chan struct{}), &started}
+	db.UnderlyingDatabase = s
+
+	//Stalled reads past the limit give up without starting.
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		if _, err := db.GetContext(ctx, keyone); err != context.DeadlineExceeded {
+			t.Fatal("Expected DeadlineExceeded from a stalled Get, got: ", err)
+		}
+		cancel()
+	}
+	if n := atomic.LoadInt32(&started); n != 2 {
+		t.Fatalf("%d stalled Gets started with 2 workers", n)
+	}
+
+	close(s.release)
+	if err := db.Close(); err != nil {
+		t.Fatal("Error closing DB: ", err)
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"github.com/TShadwell/go-useful/errors"
 	"github.com/TShadwell/level"
 	glvl "github.com/TShadwell/level/golevel"
+	"github.com/TShadwell/level/leveltest"
 	lvigo "github.com/TShadwell/level/levigo"
+	"github.com/TShadwell/level/memlevel"
+	"os"
 	"testing"
 )
 
@@ -18,7 +21,7 @@ var (
 )
 
 func TestDatabase(t *testing.T) {
-	for _, v := range []*level.Level{glvl.Level, lvigo.Level} {
+	for _, v := range []*level.Level{glvl.Level, lvigo.Level, memlevel.Level} {
 		Tdb(t, v)
 	}
 }
@@ -47,6 +50,8 @@ func Tdb(t *testing.T, lvl *level.Level) {
 		valuetwo,
 	)
 
+	t.Log("Writing atom: ", writeAtom)
+
 	err = db.Commit(
 		writeAtom,
 	)
@@ -55,8 +60,6 @@ func Tdb(t *testing.T, lvl *level.Level) {
 		t.Fatal("Error performing atomic DB write: ", errors.Extend(err))
 	}
 
-	t.Log("Atom written: ", writeAtom)
-
 	v, err := db.Get(
 		keyone,
 	)
@@ -82,6 +85,40 @@ func Tdb(t *testing.T, lvl *level.Level) {
 		t.Fatal("Values stored and retrived are not the same!")
 	}
 
+	//Iterate over the values in the DB.
+
+	it := db.NewIterator()
+	var seen int
+	for it.Seek(keyone); it.Valid(); it.Next() {
+		switch {
+		case bytes.Equal(it.Key(), keyone):
+			if !bytes.Equal(it.Value(), valueone) {
+				t.Fatal("Iterated value for key one is incorrect!")
+			}
+		case bytes.Equal(it.Key(), keytwo):
+			if !bytes.Equal(it.Value(), valuetwo) {
+				t.Fatal("Iterated value for key two is incorrect!")
+			}
+		default:
+			continue
+		}
+		seen++
+	}
+	if err = it.Err(); err != nil {
+		t.Fatal("Error whilst iterating: ", errors.Extend(err))
+	}
+	it.Close()
+
+	if seen != 2 {
+		t.Fatal("Expected to iterate over 2 keys, saw ", seen)
+	}
+
+	snap, err := db.NewSnapshot()
+	if err != nil {
+		t.Fatal("Error taking snapshot: ", errors.Extend(err))
+	}
+	defer snap.Close()
+
 	//Delete the values from the DB.
 
 	err = db.Commit(
@@ -96,4 +133,109 @@ func Tdb(t *testing.T, lvl *level.Level) {
 		t.Fatal("Could not delete added keys: ", err)
 	}
 
+	//The snapshot should not observe the deletion.
+
+	v, err = snap.Get(
+		keyone,
+	)
+	if err != nil {
+		t.Fatal("Error retrieving key one from snapshot: ", errors.Extend(err))
+	}
+
+	if !bytes.Equal(v, valueone) {
+		t.Fatal("Snapshot observed a later deletion!")
+	}
+
+	//Missing keys and empty values must be distinguishable.
+
+	if _, err = db.Get(keyone); err != level.ErrNotFound {
+		t.Fatal("Expected ErrNotFound for deleted key, got: ", err)
+	}
+
+	if has, err := db.Has(keyone); err != nil || has {
+		t.Fatal("Has reported a deleted key as present: ", err)
+	}
+
+	if err = db.Put(keyone, level.Value{}); err != nil {
+		t.Fatal("Error storing empty value: ", errors.Extend(err))
+	}
+
+	if has, err := db.Has(keyone); err != nil || !has {
+		t.Fatal("Has reported a key with an empty value as missing: ", err)
+	}
+
+	if err = db.Delete(keyone); err != nil {
+		t.Fatal("Error deleting empty value: ", errors.Extend(err))
+	}
+
+	v, err = db.SetNilOnNotFound(true).Get(keyone)
+	if err != nil || v != nil {
+		t.Fatal("Expected a nil value and error in compatibility mode, got: ", v, err)
+	}
+	db.SetNilOnNotFound(false)
+
+}
+
+func TestDestroyRepair(t *testing.T) {
+	for _, v := range []*level.Level{glvl.Level, lvigo.Level, memlevel.Level} {
+		Tdestroy(t, v)
+	}
+}
+
+func Tdestroy(t *testing.T, lvl *level.Level) {
+	path, err := osext.ExecutableFolder()
+	if err != nil {
+		panic(err)
+	}
+	location := path + "/leveldb-destroy/"
+
+	db := &level.Database{
+		Options: lvl.NewOptions().SetCreateIfMissing(
+			true,
+		),
+	}
+	if err = lvl.OpenDatabase(db, location); err != nil {
+		t.Fatal("Error whilst loading DB: ", errors.Extend(err))
+	}
+
+	if err = db.Put(keyone, valueone); err != nil {
+		t.Fatal("Error storing value: ", errors.Extend(err))
+	}
+	if err = db.Close(); err != nil {
+		t.Fatal("Error closing DB: ", errors.Extend(err))
+	}
+
+	if err = lvl.RepairDatabase(location, nil); err != nil {
+		t.Fatal("Error repairing DB: ", errors.Extend(err))
+	}
+
+	db = &level.Database{}
+	if err = lvl.OpenDatabase(db, location); err != nil {
+		t.Fatal("Error whilst reloading repaired DB: ", errors.Extend(err))
+	}
+
+	v, err := db.Get(keyone)
+	if err != nil {
+		t.Fatal("Error retrieving value from repaired DB: ", errors.Extend(err))
+	}
+	if !bytes.Equal(v, valueone) {
+		t.Fatal("Repaired DB lost a value!")
+	}
+	if err = db.Close(); err != nil {
+		t.Fatal("Error closing repaired DB: ", errors.Extend(err))
+	}
+
+	if err = lvl.DestroyDatabase(location, nil); err != nil {
+		t.Fatal("Error destroying DB: ", errors.Extend(err))
+	}
+
+	if _, err = os.Stat(location); !os.IsNotExist(err) {
+		t.Fatal("Destroyed DB still exists: ", err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	for _, v := range []*level.Level{glvl.Level, lvigo.Level, memlevel.Level} {
+		leveltest.Run(t, v)
+	}
 }
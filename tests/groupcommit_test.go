@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/TShadwell/level"
+	"github.com/TShadwell/level/leveltest"
+	"github.com/TShadwell/level/memlevel"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//writeCounter counts the writes made to a database.
+type writeCounter struct {
+	level.UnderlyingDatabase
+	writes int32
+}
+
+func (w *writeCounter) Put(o level.UnderlyingWriteOptions, k level.Key, v level.Value) error {
+	atomic.AddInt32(&w.writes, 1)
+	return w.UnderlyingDatabase.Put(o, k, v)
+}
+
+func (w *writeCounter) Delete(o level.UnderlyingWriteOptions, k level.Key) error {
+	atomic.AddInt32(&w.writes, 1)
+	return w.UnderlyingDatabase.Delete(o, k)
+}
+
+func (w *writeCounter) Write(o level.UnderlyingWriteOptions, b level.UnderlyingWriteBatch) error {
+	atomic.AddInt32(&w.writes, 1)
+	return w.UnderlyingDatabase.Write(o, b)
+}
+
+func TestGroupCommit(t *testing.T) {
+	lvl := memlevel.Level
+	db := leveltest.Open(t, lvl, "groupcommit")
+
+	counter := &writeCounter{UnderlyingDatabase: db.UnderlyingDatabase}
+	db.UnderlyingDatabase = counter
+	db.WriteOptions.SetSync(true)
+	db.SetGroupCommit(50 * time.Millisecond)
+
+	const writers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			k := level.Key(fmt.Sprint("key-", i))
+			switch i % 3 {
+			case 0:
+				errs <- db.Put(k, level.Value("put"))
+			case 1:
+				errs <- db.Write(
+					lvl.NewAtom().Put(
+						k,
+						level.Value("atom"),
+					).Delete(
+						level.Key("never"),
+					),
+				)
+			default:
+				errs <- db.Delete(k)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal("Error in grouped write: ", err)
+		}
+	}
+
+	//Scheduling may split the writers, but not one write each.
+	if n := atomic.LoadInt32(&counter.writes); n > writers/5 {
+		t.Fatalf("%d writers made %d writes", writers, n)
+	}
+
+	for i := 0; i < writers; i++ {
+		k := level.Key(fmt.Sprint("key-", i))
+		v, err := db.Get(k)
+		switch i % 3 {
+		case 0:
+			if string(v) != "put" {
+				t.Fatalf("Value at %s is %q, %v", k, v, err)
+			}
+		case 1:
+			if string(v) != "atom" {
+				t.Fatalf("Value at %s is %q, %v", k, v, err)
+			}
+		default:
+			if err != level.ErrNotFound {
+				t.Fatalf("Deleted %s is %q, %v", k, v, err)
+			}
+		}
+	}
+
+	//Writes with their own options are not grouped.
+	w := lvl.NewWriteOptions()
+	defer w.Close()
+	before := atomic.LoadInt32(&counter.writes)
+	if err := db.PutWith(w, level.Key("alone"), level.Value("x")); err != nil {
+		t.Fatal("Error in ungrouped write: ", err)
+	}
+	if n := atomic.LoadInt32(&counter.writes) - before; n != 1 {
+		t.Fatalf("Put with its own options made %d writes", n)
+	}
+
+	//Writes given nil options use the Database's, and so are grouped.
+	before = atomic.LoadInt32(&counter.writes)
+	errs = make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- db.PutWith(nil, level.Key(fmt.Sprint("nil-", i)), level.Value("x"))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal("Error in grouped write: ", err)
+		}
+	}
+	if n := atomic.LoadInt32(&counter.writes) - before; n > writers/5 {
+		t.Fatalf("%d writers with nil options made %d writes", writers, n)
+	}
+
+	//Once disabled, each write is its own.
+	db.SetGroupCommit(0)
+	before = atomic.LoadInt32(&counter.writes)
+	if err := db.Put(level.Key("alone"), level.Value("x")); err != nil {
+		t.Fatal("Error in ungrouped write: ", err)
+	}
+	if n := atomic.LoadInt32(&counter.writes) - before; n != 1 {
+		t.Fatalf("Ungrouped Put made %d writes", n)
+	}
+}
+
+func TestGroupCommitClose(t *testing.T) {
+	db := leveltest.Open(t, memlevel.Level, "groupcommitclose")
+	db.SetGroupCommit(50 * time.Millisecond)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- db.Put(level.Key("k"), level.Value("v"))
+	}()
+	//Let the Put lead its group, then Close within its window.
+	time.Sleep(10 * time.Millisecond)
+	if err := db.Close(); err != nil {
+		t.Fatal("Error whilst closing DB: ", err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal("Grouped Put failed across Close: ", err)
+	}
+}
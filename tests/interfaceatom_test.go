@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"github.com/TShadwell/level"
+	"github.com/TShadwell/level/leveltest"
+	"github.com/TShadwell/level/memlevel"
+	"testing"
+)
+
+type Can struct {
+	Name, Contents string
+}
+
+func (c Can) MarshalKey() level.Key {
+	return level.Key(c.Name)
+}
+
+func (c Can) MarshalValue() level.Value {
+	return level.Value(c.Contents)
+}
+
+func (c *Can) UnmarshalValue(v level.Value) error {
+	c.Contents = string(v)
+	return nil
+}
+
+func TestInterfaceAtom(t *testing.T) {
+	lvl := memlevel.Level
+	db := leveltest.Open(t, lvl, "interfaceatom")
+
+	if err := db.PutKV(Can{"tin", "rust"}); err != nil {
+		t.Fatal("Error storing KeyValueMarshaler: ", err)
+	}
+
+	err := db.Commit(
+		lvl.NewInterfaceAtom().PutKV(
+			Can{"beans", "can"},
+		).Put(
+			level.Key("soup"),
+			level.Value("tomato"),
+		).Delete(
+			Can{Name: "tin"},
+		),
+	)
+	if err != nil {
+		t.Fatal("Error performing atomic DB write: ", err)
+	}
+
+	beans := Can{Name: "beans"}
+	if err = db.GetInto(beans, &beans); err != nil {
+		t.Fatal("Error retrieving into ValueUnmarshaler: ", err)
+	}
+	if beans.Contents != "can" {
+		t.Fatal("Retrieved ", beans.Contents, ", expected can")
+	}
+
+	var soup level.Value
+	if err = db.GetInto(level.Key("soup"), &soup); err != nil {
+		t.Fatal("Error retrieving into Value: ", err)
+	}
+	if string(soup) != "tomato" {
+		t.Fatal("Retrieved ", string(soup), ", expected tomato")
+	}
+
+	if err = db.GetInto(Can{Name: "tin"}, new(Can)); err != level.ErrNotFound {
+		t.Fatal("Expected ErrNotFound for deleted key, got: ", err)
+	}
+
+	//Absence is reported even in compatibility mode.
+	tin := Can{Name: "tin", Contents: "untouched"}
+	if err = db.SetNilOnNotFound(true).GetInto(tin, &tin); err != level.ErrNotFound {
+		t.Fatal("Expected ErrNotFound in compatibility mode, got: ", err)
+	}
+	if tin.Contents != "untouched" {
+		t.Fatal("Missing key was unmarshaled as ", tin.Contents)
+	}
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/TShadwell/level"
+	_ "github.com/TShadwell/level/memlevel"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	db, err := level.Open("memlevel:///open-test?create=1&cache=1MB&sync=true")
+	if err != nil {
+		t.Fatal("Error opening DB by URL: ", err)
+	}
+
+	if err = db.Put(keyone, valueone); err != nil {
+		t.Fatal("Error storing value: ", err)
+	}
+
+	v, err := db.Get(keyone)
+	if err != nil {
+		t.Fatal("Error retrieving value: ", err)
+	}
+	if !bytes.Equal(v, valueone) {
+		t.Fatal("Values stored and retrived are not the same!")
+	}
+
+	if err = db.Close(); err != nil {
+		t.Fatal("Error closing DB: ", err)
+	}
+
+	for _, dsn := range []string{
+		"nosuchlevel:///open-test",
+		"memlevel:///open-test?cache=lots",
+		"memlevel:///open-test?unknown=1",
+		"memlevel:///never-created",
+	} {
+		if db, err = level.Open(dsn); err == nil {
+			db.Close()
+			t.Fatal("Expected an error opening ", dsn)
+		}
+	}
+}
+
+func TestParseBytesSize(t *testing.T) {
+	for in, out := range map[string]level.BytesSize{
+		"10":   10,
+		"10B":  10,
+		"2kb":  2 * level.Kilobyte,
+		"64MB": 64 * level.Megabyte,
+		"1 GB": level.Gigabyte,
+	} {
+		size, err := level.ParseBytesSize(in)
+		if err != nil {
+			t.Fatal("Error parsing ", in, ": ", err)
+		}
+		if size != out {
+			t.Fatal("Parsed ", in, " as ", size, ", expected ", out)
+		}
+	}
+
+	if _, err := level.ParseBytesSize("MB"); err == nil {
+		t.Fatal("Expected an error parsing a unit alone")
+	}
+
+	if size, err := level.ParseBytesSize("20000000000GB"); err == nil {
+		t.Fatal("Expected an error parsing a size which overflows, got ", size)
+	}
+}
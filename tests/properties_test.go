@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"github.com/TShadwell/level"
+	"testing"
+	"time"
+)
+
+//As given by LevelDB.
+const leveldbStats = `                               Compactions
+Level  Files Size(MB) Time(sec) Read(MB) Write(MB)
+--------------------------------------------------
+  0        2        1         0        0         1
+  1        5       10         2       12        10
+`
+
+//As given by goleveldb.
+const goleveldbStats = ` Compactions
+ Level |   Tables   |    Size(MB)   |    Time(sec)  |    Read(MB)   |   Write(MB)
+-------+------------+---------------+---------------+---------------+---------------
+   0   |          2 |       1.00000 |       0.00000 |       0.00000 |       1.00000
+   1   |          5 |      10.00000 |       2.00000 |      12.00000 |      10.00000
+`
+
+func TestParseStats(t *testing.T) {
+	for _, s := range []string{leveldbStats, goleveldbStats} {
+		stats := level.ParseStats(s)
+		if len(stats.Levels) != 2 {
+			t.Fatalf("Parsed %d levels, expected 2 from:\n%s", len(stats.Levels), s)
+		}
+
+		l := stats.Levels[1]
+		if l.Level != 1 || l.Files != 5 || l.Size != 10*level.Megabyte ||
+			l.Time != 2*time.Second || l.Read != 12*level.Megabyte || l.Write != 10*level.Megabyte {
+			t.Fatalf("Parsed level 1 incorrectly: %+v", l)
+		}
+
+		if stats.Files() != 7 {
+			t.Fatal("Expected 7 files in total, got ", stats.Files())
+		}
+		if stats.Size() != 11*level.Megabyte {
+			t.Fatal("Expected 11MB in total, got ", stats.Size())
+		}
+	}
+}